@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// preservedValuesAnnotation holds the v1alpha1 spec values that v1beta1 cannot represent exactly, such as
+// a port that is not a number or a quantity that does not parse, as a JSON object keyed by field path.
+// It is set on the v1beta1 object and removed again when converting back, so that a v1alpha1 object
+// converted to v1beta1 and back keeps these values unchanged.
+const preservedValuesAnnotation = "observability.aggregator.io/v1alpha1-values"
+
+// preservedValues maps field paths to v1alpha1 values that did not convert exactly.
+type preservedValues map[string]string
+
+// preserve records value for path if converting it to v1beta1 and back does not return it, that is
+// if it differs from its canonical form. Empty values are not recorded: they stand for the default,
+// which v1beta1 represents as well.
+func (p preservedValues) preserve(path, value string, canonical func(string) string) {
+	if value != "" && canonical(value) != value {
+		p[path] = value
+	}
+}
+
+// restore returns the value recorded for path if it still has the canonical form converted, that is
+// if the field was not changed through v1beta1 since it was recorded, and converted otherwise.
+func (p preservedValues) restore(path, converted string, canonical func(string) string) string {
+	if value, ok := p[path]; ok && canonical(value) == converted {
+		return value
+	}
+	return converted
+}
+
+// saveTo stores the values in the annotation of a v1beta1 object, or removes the annotation if there are none.
+func (p preservedValues) saveTo(meta *metav1.ObjectMeta) error {
+	if len(p) == 0 {
+		delete(meta.Annotations, preservedValuesAnnotation)
+		return nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[preservedValuesAnnotation] = string(data)
+	return nil
+}
+
+// takePreservedValues removes the annotation from an object converted from v1beta1 and returns its values.
+// An annotation that does not parse is dropped.
+func takePreservedValues(meta *metav1.ObjectMeta) preservedValues {
+	p := preservedValues{}
+	data, ok := meta.Annotations[preservedValuesAnnotation]
+	if !ok {
+		return p
+	}
+	delete(meta.Annotations, preservedValuesAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		return preservedValues{}
+	}
+	return p
+}
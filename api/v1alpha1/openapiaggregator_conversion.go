@@ -0,0 +1,266 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	observabilityv1beta1 "github.com/hellices/openapi-aggregator-operator/api/v1beta1"
+)
+
+// ConvertTo converts this OpenAPIAggregator (v1alpha1) to the Hub version (v1beta1).
+func (src *OpenAPIAggregator) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*observabilityv1beta1.OpenAPIAggregator)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	preserved := preservedValues{}
+	preserved.preserve("spec.defaultPort", src.Spec.DefaultPort, canonicalPort)
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = observabilityv1beta1.OpenAPIAggregatorSpec{
+		LabelSelector:                labelSelectorToHub(spec.LabelSelector, spec.LabelSelectorExpressions),
+		WatchNamespaces:              spec.WatchNamespaces,
+		DefaultPath:                  spec.DefaultPath,
+		DefaultPort:                  parsePort(spec.DefaultPort),
+		SwaggerAnnotation:            spec.SwaggerAnnotation,
+		PathAnnotation:               spec.PathAnnotation,
+		PortAnnotation:               spec.PortAnnotation,
+		AllowedMethodsAnnotation:     spec.AllowedMethodsAnnotation,
+		SchemeAnnotation:             spec.SchemeAnnotation,
+		URLAnnotation:                spec.URLAnnotation,
+		TLSServerNameAnnotation:      spec.TLSServerNameAnnotation,
+		InsecureSkipVerifyAnnotation: spec.InsecureSkipVerifyAnnotation,
+		AllowURLOverride:             spec.AllowURLOverride,
+		AllowInsecureSkipVerify:      spec.AllowInsecureSkipVerify,
+		ResyncInterval:               spec.ResyncInterval,
+		ConvertSwagger2:              spec.ConvertSwagger2,
+		ServerRewrite:                (*observabilityv1beta1.ServerRewriteSpec)(spec.ServerRewrite),
+		Output: observabilityv1beta1.OutputSpec{
+			ConfigMapName:        spec.Output.ConfigMapName,
+			StoreSpecs:           spec.Output.StoreSpecs,
+			CompressSpecs:        spec.Output.CompressSpecs,
+			RevisionHistoryLimit: spec.Output.RevisionHistoryLimit,
+			Merged:               (*observabilityv1beta1.MergedOutputSpec)(spec.Output.Merged),
+			OCI:                  (*observabilityv1beta1.OCIOutputSpec)(spec.Output.OCI),
+		},
+	}
+
+	status := src.Status.DeepCopy()
+	dst.Status = observabilityv1beta1.OpenAPIAggregatorStatus{
+		ObservedGeneration: status.ObservedGeneration,
+		Conditions:         status.Conditions,
+		DiscoveredAPIs:     status.DiscoveredAPIs,
+		HealthyAPIs:        status.HealthyAPIs,
+		FailedAPIs:         status.FailedAPIs,
+		LastSyncTime:       status.LastSyncTime,
+		Output:             observabilityv1beta1.OutputStatus(status.Output),
+		OCI:                (*observabilityv1beta1.OCIStatus)(status.OCI),
+	}
+	for _, api := range status.CollectedAPIs {
+		dst.Status.CollectedAPIs = append(dst.Status.CollectedAPIs, apiInfoToHub(api))
+	}
+	for _, namespaceError := range status.NamespaceErrors {
+		dst.Status.NamespaceErrors = append(dst.Status.NamespaceErrors, observabilityv1beta1.NamespaceError(namespaceError))
+	}
+	for _, conflict := range status.MergeConflicts {
+		dst.Status.MergeConflicts = append(dst.Status.MergeConflicts, observabilityv1beta1.MergeConflict(conflict))
+	}
+
+	return preserved.saveTo(&dst.ObjectMeta)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *OpenAPIAggregator) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*observabilityv1beta1.OpenAPIAggregator)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	preserved := takePreservedValues(&dst.ObjectMeta)
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = OpenAPIAggregatorSpec{
+		WatchNamespaces:              spec.WatchNamespaces,
+		DefaultPath:                  spec.DefaultPath,
+		DefaultPort:                  preserved.restore("spec.defaultPort", formatPort(spec.DefaultPort), canonicalPort),
+		SwaggerAnnotation:            spec.SwaggerAnnotation,
+		PathAnnotation:               spec.PathAnnotation,
+		PortAnnotation:               spec.PortAnnotation,
+		AllowedMethodsAnnotation:     spec.AllowedMethodsAnnotation,
+		SchemeAnnotation:             spec.SchemeAnnotation,
+		URLAnnotation:                spec.URLAnnotation,
+		TLSServerNameAnnotation:      spec.TLSServerNameAnnotation,
+		InsecureSkipVerifyAnnotation: spec.InsecureSkipVerifyAnnotation,
+		AllowURLOverride:             spec.AllowURLOverride,
+		AllowInsecureSkipVerify:      spec.AllowInsecureSkipVerify,
+		ResyncInterval:               spec.ResyncInterval,
+		ConvertSwagger2:              spec.ConvertSwagger2,
+		ServerRewrite:                (*ServerRewriteSpec)(spec.ServerRewrite),
+		Output: OutputSpec{
+			ConfigMapName:        spec.Output.ConfigMapName,
+			StoreSpecs:           spec.Output.StoreSpecs,
+			CompressSpecs:        spec.Output.CompressSpecs,
+			RevisionHistoryLimit: spec.Output.RevisionHistoryLimit,
+			Merged:               (*MergedOutputSpec)(spec.Output.Merged),
+			OCI:                  (*OCIOutputSpec)(spec.Output.OCI),
+		},
+	}
+
+	if spec.LabelSelector != nil {
+		dst.Spec.LabelSelector = spec.LabelSelector.MatchLabels
+		dst.Spec.LabelSelectorExpressions = spec.LabelSelector.MatchExpressions
+	}
+
+	status := src.Status.DeepCopy()
+	dst.Status = OpenAPIAggregatorStatus{
+		ObservedGeneration: status.ObservedGeneration,
+		Conditions:         status.Conditions,
+		DiscoveredAPIs:     status.DiscoveredAPIs,
+		HealthyAPIs:        status.HealthyAPIs,
+		FailedAPIs:         status.FailedAPIs,
+		LastSyncTime:       status.LastSyncTime,
+		Output:             OutputStatus(status.Output),
+		OCI:                (*OCIStatus)(status.OCI),
+	}
+	for _, api := range status.CollectedAPIs {
+		dst.Status.CollectedAPIs = append(dst.Status.CollectedAPIs, apiInfoFromHub(api))
+	}
+	for _, namespaceError := range status.NamespaceErrors {
+		dst.Status.NamespaceErrors = append(dst.Status.NamespaceErrors, NamespaceError(namespaceError))
+	}
+	for _, conflict := range status.MergeConflicts {
+		dst.Status.MergeConflicts = append(dst.Status.MergeConflicts, MergeConflict(conflict))
+	}
+	return nil
+}
+
+// apiInfoToHub converts a collected API to v1beta1.
+func apiInfoToHub(api APIInfo) observabilityv1beta1.APIInfo {
+	converted := observabilityv1beta1.APIInfo{
+		Name:               api.Name,
+		URL:                api.URL,
+		Error:              api.Error,
+		ResourceType:       api.ResourceType,
+		ResourceName:       api.ResourceName,
+		Namespace:          api.Namespace,
+		Path:               api.Path,
+		Port:               api.Port,
+		Annotations:        api.Annotations,
+		AllowedMethods:     api.AllowedMethods,
+		HTTPStatus:         api.HTTPStatus,
+		SpecVersion:        api.SpecVersion,
+		ServerURL:          api.ServerURL,
+		ConvertedFrom:      api.ConvertedFrom,
+		ConversionWarnings: api.ConversionWarnings,
+		PrunedOperations:   api.PrunedOperations,
+		Title:              api.Title,
+		Version:            api.Version,
+	}
+	if updated, err := time.Parse(time.RFC3339, api.LastUpdated); err == nil {
+		converted.LastUpdated = &metav1.Time{Time: updated}
+	}
+	for _, revision := range api.Revisions {
+		hubRevision := observabilityv1beta1.SpecRevision{
+			Hash:                   revision.Hash,
+			CollectedAt:            revision.CollectedAt,
+			ServiceResourceVersion: revision.ServiceResourceVersion,
+			Key:                    revision.Key,
+			BreakingChanges:        revision.BreakingChanges,
+			NonBreakingChanges:     revision.NonBreakingChanges,
+		}
+		for _, change := range revision.Changes {
+			hubRevision.Changes = append(hubRevision.Changes, observabilityv1beta1.SpecChange(change))
+		}
+		converted.Revisions = append(converted.Revisions, hubRevision)
+	}
+	return converted
+}
+
+// apiInfoFromHub converts a collected API from v1beta1.
+func apiInfoFromHub(api observabilityv1beta1.APIInfo) APIInfo {
+	converted := APIInfo{
+		Name:               api.Name,
+		URL:                api.URL,
+		Error:              api.Error,
+		ResourceType:       api.ResourceType,
+		ResourceName:       api.ResourceName,
+		Namespace:          api.Namespace,
+		Path:               api.Path,
+		Port:               api.Port,
+		Annotations:        api.Annotations,
+		AllowedMethods:     api.AllowedMethods,
+		HTTPStatus:         api.HTTPStatus,
+		SpecVersion:        api.SpecVersion,
+		ServerURL:          api.ServerURL,
+		ConvertedFrom:      api.ConvertedFrom,
+		ConversionWarnings: api.ConversionWarnings,
+		PrunedOperations:   api.PrunedOperations,
+		Title:              api.Title,
+		Version:            api.Version,
+	}
+	if api.LastUpdated != nil {
+		converted.LastUpdated = api.LastUpdated.UTC().Format(time.RFC3339)
+	}
+	for _, hubRevision := range api.Revisions {
+		revision := SpecRevision{
+			Hash:                   hubRevision.Hash,
+			CollectedAt:            hubRevision.CollectedAt,
+			ServiceResourceVersion: hubRevision.ServiceResourceVersion,
+			Key:                    hubRevision.Key,
+			BreakingChanges:        hubRevision.BreakingChanges,
+			NonBreakingChanges:     hubRevision.NonBreakingChanges,
+		}
+		for _, change := range hubRevision.Changes {
+			revision.Changes = append(revision.Changes, SpecChange(change))
+		}
+		converted.Revisions = append(converted.Revisions, revision)
+	}
+	return converted
+}
+
+// labelSelectorToHub returns the v1beta1 label selector made of the v1alpha1 labels and expressions,
+// or nil if neither is set.
+func labelSelectorToHub(matchLabels map[string]string, matchExpressions []metav1.LabelSelectorRequirement) *metav1.LabelSelector {
+	if len(matchLabels) == 0 && len(matchExpressions) == 0 {
+		return nil
+	}
+	return &metav1.LabelSelector{MatchLabels: matchLabels, MatchExpressions: matchExpressions}
+}
+
+// parsePort returns the number of a port, or 0 if it is not a number.
+func parsePort(port string) int32 {
+	number, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(number)
+}
+
+// formatPort returns the v1alpha1 representation of a port number; 0 stands for the default.
+func formatPort(port int32) string {
+	if port == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(port))
+}
+
+// canonicalPort returns the form a v1alpha1 port has after converting it to v1beta1 and back.
+func canonicalPort(port string) string {
+	return formatPort(parsePort(port))
+}
@@ -22,14 +22,23 @@ import (
 
 // OpenAPIAggregatorSpec defines the desired state of OpenAPIAggregator
 type OpenAPIAggregatorSpec struct {
-	// LabelSelector selects target deployments to collect OpenAPI specs from
-	// This field is currently not used if WatchNamespaces is implemented as primary.
-	// Consider removing or marking as deprecated if WatchNamespaces becomes the sole method.
+	// LabelSelector restricts discovery to Services carrying all of these labels.
+	// When set, or when labelSelectorExpressions is set, matching Services are collected even without
+	// the swagger annotation; a Service can still opt out by setting the swagger annotation to a value
+	// other than "true". When neither is set, only Services carrying the swagger annotation are collected.
+	// +optional
 	LabelSelector map[string]string `json:"labelSelector,omitempty"`
 
+	// LabelSelectorExpressions further restricts discovery to Services whose labels satisfy all of
+	// these requirements. It is the matchExpressions part of the v1beta1 labelSelector.
+	// +optional
+	LabelSelectorExpressions []metav1.LabelSelectorRequirement `json:"labelSelectorExpressions,omitempty"`
+
 	// WatchNamespaces specifies a list of namespaces to watch for services.
 	// If empty or not provided, the controller will watch services in the same namespace as the OpenAPIAggregator CR.
-	// If set to [""] (a list containing a single empty string), the controller will watch services in all namespaces.
+	// If set to [""] or ["*"], the controller will watch services in all namespaces.
+	// Otherwise services are listed from each namespace in the list and the results are merged;
+	// namespaces that cannot be listed are reported in status.namespaceErrors.
 	// Requires appropriate RBAC permissions for watching services in the specified namespaces (e.g., ClusterRole for all namespaces).
 	// +optional
 	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
@@ -38,7 +47,8 @@ type OpenAPIAggregatorSpec struct {
 	// +kubebuilder:default="/v2/api-docs"
 	DefaultPath string `json:"defaultPath,omitempty"`
 
-	// DefaultPort is the default port for OpenAPI documentation
+	// DefaultPort is the default port for OpenAPI documentation, given as the name or number of a Service port.
+	// If a Service does not expose it and has a single port, that port is used instead.
 	// +kubebuilder:default="8080"
 	DefaultPort string `json:"defaultPort,omitempty"`
 
@@ -50,19 +60,259 @@ type OpenAPIAggregatorSpec struct {
 	// +kubebuilder:default="openapi.aggregator.io/path"
 	PathAnnotation string `json:"pathAnnotation,omitempty"`
 
-	// PortAnnotation is the annotation key for OpenAPI port
+	// PortAnnotation is the annotation key for OpenAPI port, given as the name or number of a Service port
 	// +kubebuilder:default="openapi.aggregator.io/port"
 	PortAnnotation string `json:"portAnnotation,omitempty"`
 
 	// AllowedMethodsAnnotation is the annotation key for allowed HTTP methods in Swagger UI
 	// +kubebuilder:default="openapi.aggregator.io/allowed-methods"
 	AllowedMethodsAnnotation string `json:"allowedMethodsAnnotation,omitempty"`
+
+	// SchemeAnnotation is the annotation key for the scheme ("http" or "https") the spec is fetched with
+	// +kubebuilder:default="openapi.aggregator.io/scheme"
+	SchemeAnnotation string `json:"schemeAnnotation,omitempty"`
+
+	// URLAnnotation is the annotation key for the full URL the spec is fetched from, e.g. when it is served
+	// by another Service or a headless address. It takes precedence over the scheme, port and path annotations.
+	// +kubebuilder:default="openapi.aggregator.io/url"
+	URLAnnotation string `json:"urlAnnotation,omitempty"`
+
+	// TLSServerNameAnnotation is the annotation key for the name the server certificate is verified against
+	// when fetching the spec over HTTPS, if it differs from the host of the URL
+	// +kubebuilder:default="openapi.aggregator.io/tls-server-name"
+	TLSServerNameAnnotation string `json:"tlsServerNameAnnotation,omitempty"`
+
+	// InsecureSkipVerifyAnnotation is the annotation key that, set to "true", disables verification of the
+	// server certificate when fetching the spec over HTTPS
+	// +kubebuilder:default="openapi.aggregator.io/insecure-skip-verify"
+	InsecureSkipVerifyAnnotation string `json:"insecureSkipVerifyAnnotation,omitempty"`
+
+	// AllowURLOverride honors the URL annotation. It is off by default because the annotation lets anyone
+	// who can annotate a selected Service make the operator fetch from any address it can reach; while off,
+	// an annotated Service is reported with an error and its spec is not fetched.
+	// +optional
+	AllowURLOverride bool `json:"allowURLOverride,omitempty"`
+
+	// AllowInsecureSkipVerify honors the insecure-skip-verify annotation. While off, server certificates
+	// are always verified.
+	// +optional
+	AllowInsecureSkipVerify bool `json:"allowInsecureSkipVerify,omitempty"`
+
+	// ResyncInterval is how often the spec of each collected API is fetched again. Services are watched,
+	// so discovery changes are picked up as they happen; re-fetches are spread with jitter, and failing
+	// specs are retried with exponential backoff instead. Defaults to 5m; the minimum is 10s.
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// ConvertSwagger2 converts fetched Swagger 2.0 specs to OpenAPI 3.0, so that stored and merged
+	// output uses a uniform format. Conversion warnings are reported on the API's status entry.
+	// +optional
+	ConvertSwagger2 bool `json:"convertSwagger2,omitempty"`
+
+	// ServerRewrite rewrites the servers (OpenAPI 3) or host, basePath and schemes (Swagger 2.0)
+	// of collected specs, so that "Try it out" in the Swagger UI reaches the service instead of
+	// whatever address the backend advertises. The path of each declared server is kept.
+	// +optional
+	ServerRewrite *ServerRewriteSpec `json:"serverRewrite,omitempty"`
+
+	// Output configures what the aggregator writes for the collected APIs
+	// +optional
+	Output OutputSpec `json:"output,omitempty"`
+}
+
+// ServerRewriteSpec configures the server address written into collected specs
+type ServerRewriteSpec struct {
+	// URLTemplate is the base URL written into each spec, e.g. "https://api.example.com/{namespace}/{name}".
+	// "{namespace}", "{name}" and "{port}" are replaced with the Service's namespace, name and spec port.
+	// If empty, the in-cluster address of the Service the spec was fetched from is used.
+	// +optional
+	URLTemplate string `json:"urlTemplate,omitempty"`
+}
+
+// OutputSpec configures the aggregated output of an OpenAPIAggregator
+type OutputSpec struct {
+	// ConfigMapName is the name of the ConfigMap the output is written to, in the aggregator's namespace.
+	// Defaults to "<aggregator name>-specs". The ConfigMap must not be controlled by anything else.
+	// An aggregator that wrote its output to "openapi-specs" before this field existed keeps writing it there until the field is set.
+	// +kubebuilder:validation:MaxLength=240
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// StoreSpecs stores the content of each fetched spec in the output ConfigMap, under the "spec" field
+	// of the API's entry, so the Swagger UI serves a snapshot instead of reaching every backend itself.
+	// If a spec cannot be fetched, the last successfully fetched content is kept.
+	// +optional
+	StoreSpecs bool `json:"storeSpecs,omitempty"`
+
+	// CompressSpecs stores the specs gzip-compressed in the ConfigMap's binaryData instead of in the
+	// "spec" field. The entry then names the binaryData key in "specKey", with "specEncoding" set to
+	// "gzip" and the SHA-256 "specChecksum" of the uncompressed spec. The merged document is stored
+	// under "merged.openapi.json.gz". Specs are only stored when storeSpecs is enabled.
+	// +optional
+	CompressSpecs bool `json:"compressSpecs,omitempty"`
+
+	// RevisionHistoryLimit is the number of distinct revisions kept for each collected spec in the
+	// "<configMapName>-history" ConfigMap, listed newest first in the API's "revisions". 0 disables the history.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=20
+	// +optional
+	RevisionHistoryLimit int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// Merged writes a single OpenAPI 3 document combining all collected APIs
+	// under the "merged.openapi.json" key, next to the per-API entries
+	// +optional
+	Merged *MergedOutputSpec `json:"merged,omitempty"`
+
+	// OCI pushes the collected specs to an OCI registry as an artifact whenever they change,
+	// in addition to the output ConfigMap
+	// +optional
+	OCI *OCIOutputSpec `json:"oci,omitempty"`
+}
+
+// MergedOutputSpec configures the merged OpenAPI document.
+// Paths are prefixed per API, components are renamed to "<namespace>.<name>.<component>",
+// and tags and security schemes are merged by name. Swagger 2.0 specs are skipped.
+type MergedOutputSpec struct {
+	// Title is the info.title of the merged document
+	// +kubebuilder:default="Aggregated API"
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Version is the info.version of the merged document
+	// +kubebuilder:default="1.0.0"
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// PathPrefix is prepended to the paths of each API.
+	// "{namespace}" and "{name}" are replaced with the namespace and name of the Service.
+	// +kubebuilder:default="/{namespace}/{name}"
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+}
+
+// OCIOutputSpec configures pushing the collected specs to an OCI registry.
+// Each artifact is an OCI image index referencing one manifest per collected spec, with the spec as its
+// single layer, one for the merged document if enabled, and one whose "apis.json" layer describes the
+// collected APIs.
+type OCIOutputSpec struct {
+	// Repository is the repository the artifacts are pushed to, including the registry host,
+	// e.g. "registry.example.com/platform/api-catalog"
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// TagTemplate is the tag of each pushed artifact. "{digest}" is replaced with the first 12 hex digits
+	// of the artifact's image index digest, "{timestamp}" with the UTC push time as YYYYMMDDhhmmss, and
+	// "{generation}" with the aggregator's generation.
+	// +kubebuilder:default="{digest}"
+	// +optional
+	TagTemplate string `json:"tagTemplate,omitempty"`
+
+	// CredentialsSecret is the name of a Secret in the aggregator's namespace holding the registry
+	// credentials, either of type kubernetes.io/dockerconfigjson or with "username" and "password" keys
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// Insecure connects to the registry over plain HTTP
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
 }
 
 // OpenAPIAggregatorStatus defines the observed state of OpenAPIAggregator
 type OpenAPIAggregatorStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the aggregator's state.
+	// Known condition types are Ready, Discovering, ConfigMapSynced and Degraded.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// DiscoveredAPIs is the number of APIs discovered during the last reconciliation
+	// +optional
+	DiscoveredAPIs int32 `json:"discoveredAPIs,omitempty"`
+
+	// HealthyAPIs is the number of discovered APIs whose spec was collected without error
+	// +optional
+	HealthyAPIs int32 `json:"healthyAPIs,omitempty"`
+
+	// FailedAPIs is the number of discovered APIs whose spec could not be collected
+	// +optional
+	FailedAPIs int32 `json:"failedAPIs,omitempty"`
+
+	// LastSyncTime is when the output ConfigMap was last successfully synced
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Output describes where the collected APIs were written
+	// +optional
+	Output OutputStatus `json:"output,omitempty"`
+
+	// OCI describes the last artifact pushed to the OCI registry
+	// +optional
+	OCI *OCIStatus `json:"oci,omitempty"`
+
 	// CollectedAPIs contains information about the OpenAPI specs that have been collected
 	CollectedAPIs []APIInfo `json:"collectedAPIs,omitempty"`
+
+	// NamespaceErrors lists the watched namespaces whose services could not be listed
+	// during the last reconciliation
+	// +optional
+	NamespaceErrors []NamespaceError `json:"namespaceErrors,omitempty"`
+
+	// MergeConflicts lists the elements that could not be merged as-is into the merged document
+	// +optional
+	MergeConflicts []MergeConflict `json:"mergeConflicts,omitempty"`
+}
+
+// OutputStatus describes the ConfigMaps an aggregator writes its output to
+type OutputStatus struct {
+	// ConfigMapName is the name of the output ConfigMap
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Shards lists, in order, the ConfigMaps holding the entries when the output is too large for a single
+	// ConfigMap. The output ConfigMap then only holds an index under the "index.json" key, mapping each entry
+	// to its shard. Empty when all entries are stored in the output ConfigMap itself.
+	// +optional
+	Shards []string `json:"shards,omitempty"`
+}
+
+// OCIStatus describes the artifacts pushed to the OCI registry
+type OCIStatus struct {
+	// Reference is the "<repository>:<tag>" of the last pushed artifact
+	// +optional
+	Reference string `json:"reference,omitempty"`
+
+	// Digest is the image index digest of the last pushed artifact
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// PushedAt is when the last artifact was pushed
+	// +optional
+	PushedAt *metav1.Time `json:"pushedAt,omitempty"`
+
+	// Error is the reason the last push failed, empty if it succeeded
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// MergeConflict describes an element of a collected spec that was skipped or resolved while merging
+type MergeConflict struct {
+	// API is the namespace.name key of the API the element belongs to
+	API string `json:"api"`
+
+	// Message describes the conflict and how it was resolved
+	Message string `json:"message"`
+}
+
+// NamespaceError records a failure to list services in one of the watched namespaces
+type NamespaceError struct {
+	// Namespace is the watched namespace that could not be listed
+	Namespace string `json:"namespace"`
+
+	// Error is the error returned while listing services in the namespace
+	Error string `json:"error"`
 }
 
 // APIInfo contains information about a collected OpenAPI spec
@@ -99,10 +349,96 @@ type APIInfo struct {
 
 	// AllowedMethods stores the allowed HTTP methods for Swagger UI
 	AllowedMethods []string `json:"allowedMethods,omitempty"`
+
+	// HTTPStatus is the HTTP status code returned by the last fetch of the spec
+	// +optional
+	HTTPStatus int32 `json:"httpStatus,omitempty"`
+
+	// SpecVersion is the Swagger or OpenAPI version declared by the spec (e.g. "2.0", "3.0.3").
+	// For converted specs this is the version after conversion.
+	// +optional
+	SpecVersion string `json:"specVersion,omitempty"`
+
+	// ServerURL is the base URL the spec's servers were rewritten to
+	// +optional
+	ServerURL string `json:"serverURL,omitempty"`
+
+	// ConvertedFrom is the version of the fetched spec if it was converted to OpenAPI 3.0
+	// +optional
+	ConvertedFrom string `json:"convertedFrom,omitempty"`
+
+	// ConversionWarnings lists the parts of the spec that could not be converted exactly
+	// +optional
+	ConversionWarnings []string `json:"conversionWarnings,omitempty"`
+
+	// PrunedOperations is the number of operations removed from the spec because their method is not allowed
+	// +optional
+	PrunedOperations int32 `json:"prunedOperations,omitempty"`
+
+	// Title is the info.title of the spec
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Version is the info.version of the spec
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Revisions lists the kept revisions of the spec, newest first, when spec.output.revisionHistoryLimit
+	// is set. Only the breaking changes of the newest revision are listed; their content and all their
+	// changes are stored in the history ConfigMap.
+	// +optional
+	Revisions []SpecRevision `json:"revisions,omitempty"`
+}
+
+// SpecRevision identifies a revision of a collected spec
+type SpecRevision struct {
+	// Hash is the SHA-256 checksum of the spec content, as "sha256:<hex>"
+	Hash string `json:"hash"`
+
+	// CollectedAt is when this content was collected
+	CollectedAt metav1.Time `json:"collectedAt"`
+
+	// ServiceResourceVersion is the resourceVersion of the Service when this content was collected
+	// +optional
+	ServiceResourceVersion string `json:"serviceResourceVersion,omitempty"`
+
+	// Key is the binaryData key of the gzip-compressed content in the history ConfigMap
+	Key string `json:"key"`
+
+	// BreakingChanges is the number of breaking changes from the previous revision
+	// +optional
+	BreakingChanges int32 `json:"breakingChanges,omitempty"`
+
+	// NonBreakingChanges is the number of non-breaking changes from the previous revision
+	// +optional
+	NonBreakingChanges int32 `json:"nonBreakingChanges,omitempty"`
+
+	// Changes lists the changes from the previous revision, breaking ones first, up to 20
+	// +optional
+	Changes []SpecChange `json:"changes,omitempty"`
+}
+
+// SpecChange describes a difference between two revisions of a spec
+type SpecChange struct {
+	// Breaking is true if clients written against the previous revision may fail against this one
+	Breaking bool `json:"breaking"`
+
+	// Location is where the change was found, e.g. "GET /pets/{id}"
+	Location string `json:"location"`
+
+	// Message describes the change
+	Message string `json:"message"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="DISCOVERED",type="integer",JSONPath=".status.discoveredAPIs"
+//+kubebuilder:printcolumn:name="HEALTHY",type="integer",JSONPath=".status.healthyAPIs"
+//+kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.failedAPIs"
+//+kubebuilder:printcolumn:name="CONFIGMAP",type="string",JSONPath=".status.output.configMapName"
+//+kubebuilder:printcolumn:name="LAST SYNC",type="date",JSONPath=".status.lastSyncTime"
+//+kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 
 // OpenAPIAggregator is the Schema for the openapiaggregators API
 type OpenAPIAggregator struct {
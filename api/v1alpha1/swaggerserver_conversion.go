@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	observabilityv1beta1 "github.com/hellices/openapi-aggregator-operator/api/v1beta1"
+)
+
+// ConvertTo converts this SwaggerServer (v1alpha1) to the Hub version (v1beta1).
+func (src *SwaggerServer) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*observabilityv1beta1.SwaggerServer)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	preserved := preservedValues{}
+	preserved.preserve("spec.watchIntervalSeconds", src.Spec.WatchIntervalSeconds, canonicalWatchInterval)
+	preserved.preserve("spec.devMode", src.Spec.DevMode, canonicalDevMode)
+	resources, exact := resourcesToHub(src.Spec.Resources)
+	if !exact {
+		data, err := json.Marshal(src.Spec.Resources)
+		if err != nil {
+			return err
+		}
+		preserved["spec.resources"] = string(data)
+	}
+
+	dst.Spec = observabilityv1beta1.SwaggerServerSpec{
+		ConfigMapName:   src.Spec.ConfigMapName,
+		Port:            src.Spec.Port,
+		Image:           src.Spec.Image,
+		ImagePullPolicy: corev1.PullPolicy(src.Spec.ImagePullPolicy),
+		Resources:       resources,
+		WatchInterval:   parseWatchInterval(src.Spec.WatchIntervalSeconds),
+		LogLevel:        src.Spec.LogLevel,
+		DevMode:         src.Spec.DevMode == "true",
+	}
+	dst.Status = observabilityv1beta1.SwaggerServerStatus(*src.Status.DeepCopy())
+
+	return preserved.saveTo(&dst.ObjectMeta)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *SwaggerServer) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*observabilityv1beta1.SwaggerServer)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	preserved := takePreservedValues(&dst.ObjectMeta)
+
+	dst.Spec = SwaggerServerSpec{
+		ConfigMapName:        src.Spec.ConfigMapName,
+		Port:                 src.Spec.Port,
+		Image:                src.Spec.Image,
+		ImagePullPolicy:      string(src.Spec.ImagePullPolicy),
+		Resources:            resourcesFromHub(src.Spec.Resources),
+		WatchIntervalSeconds: preserved.restore("spec.watchIntervalSeconds", formatWatchInterval(src.Spec.WatchInterval), canonicalWatchInterval),
+		LogLevel:             src.Spec.LogLevel,
+		DevMode:              preserved.restore("spec.devMode", formatDevMode(src.Spec.DevMode), canonicalDevMode),
+	}
+	if data, ok := preserved["spec.resources"]; ok {
+		var resources ResourceRequirements
+		if err := json.Unmarshal([]byte(data), &resources); err == nil {
+			if converted, _ := resourcesToHub(resources); apiequality.Semantic.DeepEqual(converted, src.Spec.Resources) {
+				dst.Spec.Resources = resources
+			}
+		}
+	}
+	dst.Status = SwaggerServerStatus(*src.Status.DeepCopy())
+	return nil
+}
+
+// resourcesToHub converts resource requirements to v1beta1. Quantities that do not parse are left out;
+// exact is false if any quantity was left out or is not in its canonical form.
+func resourcesToHub(resources ResourceRequirements) (converted corev1.ResourceRequirements, exact bool) {
+	exact = true
+	convertList := func(list ResourceList) corev1.ResourceList {
+		if list == nil {
+			return nil
+		}
+		result := corev1.ResourceList{}
+		for name, value := range list {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				exact = false
+				continue
+			}
+			if quantity.String() != value {
+				exact = false
+			}
+			result[corev1.ResourceName(name)] = quantity
+		}
+		return result
+	}
+	converted.Limits = convertList(resources.Limits)
+	converted.Requests = convertList(resources.Requests)
+	return converted, exact
+}
+
+// resourcesFromHub converts resource requirements from v1beta1. Resource claims are not supported by v1alpha1.
+func resourcesFromHub(resources corev1.ResourceRequirements) ResourceRequirements {
+	convertList := func(list corev1.ResourceList) ResourceList {
+		if list == nil {
+			return nil
+		}
+		result := ResourceList{}
+		for name, quantity := range list {
+			result[string(name)] = quantity.String()
+		}
+		return result
+	}
+	return ResourceRequirements{
+		Limits:   convertList(resources.Limits),
+		Requests: convertList(resources.Requests),
+	}
+}
+
+// parseWatchInterval returns the duration of a watch interval in seconds, or nil if it is not a number.
+func parseWatchInterval(seconds string) *metav1.Duration {
+	number, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil || number < 0 {
+		return nil
+	}
+	return &metav1.Duration{Duration: time.Duration(number) * time.Second}
+}
+
+// formatWatchInterval returns the v1alpha1 representation of a watch interval, rounded up to whole seconds.
+func formatWatchInterval(interval *metav1.Duration) string {
+	if interval == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(math.Ceil(interval.Seconds())), 10)
+}
+
+// canonicalWatchInterval returns the form a v1alpha1 watch interval has after converting it to v1beta1 and back.
+func canonicalWatchInterval(seconds string) string {
+	return formatWatchInterval(parseWatchInterval(seconds))
+}
+
+// formatDevMode returns the v1alpha1 representation of the dev mode flag.
+func formatDevMode(devMode bool) string {
+	return strconv.FormatBool(devMode)
+}
+
+// canonicalDevMode returns the form a v1alpha1 dev mode flag has after converting it to v1beta1 and back.
+func canonicalDevMode(devMode string) string {
+	return formatDevMode(devMode == "true")
+}
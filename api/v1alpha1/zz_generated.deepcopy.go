@@ -40,6 +40,18 @@ func (in *APIInfo) DeepCopyInto(out *APIInfo) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConversionWarnings != nil {
+		in, out := &in.ConversionWarnings, &out.ConversionWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]SpecRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIInfo.
@@ -52,6 +64,85 @@ func (in *APIInfo) DeepCopy() *APIInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergeConflict) DeepCopyInto(out *MergeConflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeConflict.
+func (in *MergeConflict) DeepCopy() *MergeConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(MergeConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergedOutputSpec) DeepCopyInto(out *MergedOutputSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergedOutputSpec.
+func (in *MergedOutputSpec) DeepCopy() *MergedOutputSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MergedOutputSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceError) DeepCopyInto(out *NamespaceError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceError.
+func (in *NamespaceError) DeepCopy() *NamespaceError {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIOutputSpec) DeepCopyInto(out *OCIOutputSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIOutputSpec.
+func (in *OCIOutputSpec) DeepCopy() *OCIOutputSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIOutputSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIStatus) DeepCopyInto(out *OCIStatus) {
+	*out = *in
+	if in.PushedAt != nil {
+		in, out := &in.PushedAt, &out.PushedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIStatus.
+func (in *OCIStatus) DeepCopy() *OCIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenAPIAggregator) DeepCopyInto(out *OpenAPIAggregator) {
 	*out = *in
@@ -121,11 +212,29 @@ func (in *OpenAPIAggregatorSpec) DeepCopyInto(out *OpenAPIAggregatorSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.LabelSelectorExpressions != nil {
+		in, out := &in.LabelSelectorExpressions, &out.LabelSelectorExpressions
+		*out = make([]v1.LabelSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.WatchNamespaces != nil {
 		in, out := &in.WatchNamespaces, &out.WatchNamespaces
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ServerRewrite != nil {
+		in, out := &in.ServerRewrite, &out.ServerRewrite
+		*out = new(ServerRewriteSpec)
+		**out = **in
+	}
+	in.Output.DeepCopyInto(&out.Output)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIAggregatorSpec.
@@ -141,6 +250,23 @@ func (in *OpenAPIAggregatorSpec) DeepCopy() *OpenAPIAggregatorSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenAPIAggregatorStatus) DeepCopyInto(out *OpenAPIAggregatorStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	in.Output.DeepCopyInto(&out.Output)
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CollectedAPIs != nil {
 		in, out := &in.CollectedAPIs, &out.CollectedAPIs
 		*out = make([]APIInfo, len(*in))
@@ -148,6 +274,16 @@ func (in *OpenAPIAggregatorStatus) DeepCopyInto(out *OpenAPIAggregatorStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NamespaceErrors != nil {
+		in, out := &in.NamespaceErrors, &out.NamespaceErrors
+		*out = make([]NamespaceError, len(*in))
+		copy(*out, *in)
+	}
+	if in.MergeConflicts != nil {
+		in, out := &in.MergeConflicts, &out.MergeConflicts
+		*out = make([]MergeConflict, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIAggregatorStatus.
@@ -160,6 +296,51 @@ func (in *OpenAPIAggregatorStatus) DeepCopy() *OpenAPIAggregatorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputSpec) DeepCopyInto(out *OutputSpec) {
+	*out = *in
+	if in.Merged != nil {
+		in, out := &in.Merged, &out.Merged
+		*out = new(MergedOutputSpec)
+		**out = **in
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIOutputSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputSpec.
+func (in *OutputSpec) DeepCopy() *OutputSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputStatus) DeepCopyInto(out *OutputStatus) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputStatus.
+func (in *OutputStatus) DeepCopy() *OutputStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ResourceList) DeepCopyInto(out *ResourceList) {
 	{
@@ -210,6 +391,57 @@ func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerRewriteSpec) DeepCopyInto(out *ServerRewriteSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerRewriteSpec.
+func (in *ServerRewriteSpec) DeepCopy() *ServerRewriteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerRewriteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecChange) DeepCopyInto(out *SpecChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecChange.
+func (in *SpecChange) DeepCopy() *SpecChange {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecRevision) DeepCopyInto(out *SpecRevision) {
+	*out = *in
+	in.CollectedAt.DeepCopyInto(&out.CollectedAt)
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]SpecChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecRevision.
+func (in *SpecRevision) DeepCopy() *SpecRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SwaggerServer) DeepCopyInto(out *SwaggerServer) {
 	*out = *in
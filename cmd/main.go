@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -36,7 +37,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	observabilityv1beta1 "github.com/hellices/openapi-aggregator-operator/api/v1beta1"
+	"github.com/hellices/openapi-aggregator-operator/internal/catalog"
 	"github.com/hellices/openapi-aggregator-operator/internal/controller"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+	webhookobservabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/internal/webhook/v1alpha1"
+	webhookobservabilityv1beta1 "github.com/hellices/openapi-aggregator-operator/internal/webhook/v1beta1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,6 +55,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(observabilityv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(observabilityv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -58,6 +65,10 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var specFetchTimeout time.Duration
+	var specFetchConcurrency int
+	var catalogAddr string
+	var enableWebhooks bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -69,6 +80,16 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&specFetchTimeout, "spec-fetch-timeout", openapi.DefaultTimeout,
+		"Timeout for fetching a single OpenAPI document from a discovered service.")
+	flag.IntVar(&specFetchConcurrency, "spec-fetch-concurrency", openapi.DefaultMaxConcurrency,
+		"Maximum number of OpenAPI documents fetched in parallel by one reconciliation.")
+	flag.StringVar(&catalogAddr, "catalog-bind-address", "0", "The address the API catalog endpoint binds to "+
+		"(/apis, /apis/{namespace}/{name}, /merged.json), e.g. :8082. Leave as 0 to disable it.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", true,
+		"If set, the defaulting, validating and conversion webhooks are served. "+
+			"Requires a serving certificate, see config/default for a cert-manager setup. "+
+			"The CRDs in config/crd convert through the webhook server, so only disable it for local runs.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -144,21 +165,56 @@ func main() {
 		os.Exit(1)
 	}
 
+	var apiCatalog *catalog.Catalog
+	if catalogAddr != "0" && catalogAddr != "" {
+		apiCatalog = catalog.New()
+		if err := mgr.Add(&catalog.Server{Addr: catalogAddr, Catalog: apiCatalog}); err != nil {
+			setupLog.Error(err, "unable to set up API catalog server")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controller.OpenAPIAggregatorReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		Fetcher: openapi.NewFetcher(openapi.FetcherOptions{
+			Timeout:        specFetchTimeout,
+			MaxConcurrency: specFetchConcurrency,
+		}),
+		Recorder:  mgr.GetEventRecorderFor("openapiaggregator-controller"),
+		APIReader: mgr.GetAPIReader(),
+		Catalog:   apiCatalog,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenAPIAggregator")
 		os.Exit(1)
 	}
 
 	if err = (&controller.SwaggerServerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("swaggerserver-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SwaggerServer")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		if err = webhookobservabilityv1alpha1.SetupOpenAPIAggregatorWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OpenAPIAggregator")
+			os.Exit(1)
+		}
+		if err = webhookobservabilityv1alpha1.SetupSwaggerServerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwaggerServer")
+			os.Exit(1)
+		}
+		if err = webhookobservabilityv1beta1.SetupOpenAPIAggregatorWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OpenAPIAggregator")
+			os.Exit(1)
+		}
+		if err = webhookobservabilityv1beta1.SetupSwaggerServerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwaggerServer")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,236 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package catalog keeps the APIs collected by the aggregators in memory and serves them over HTTP.
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+// Published is an API collected by an aggregator, as published to the catalog.
+type Published struct {
+	// Info describes the API
+	Info observabilityv1alpha1.APIInfo
+
+	// Spec is the JSON content of the API's document, or nil if it could not be collected
+	Spec []byte
+}
+
+// API is an API as listed by the catalog.
+type API struct {
+	observabilityv1alpha1.APIInfo `json:",inline"`
+
+	// Aggregator is the "<namespace>/<name>" of the aggregator that collected the API
+	Aggregator string `json:"aggregator"`
+
+	// SpecPath is the path the API's document is served at, if the catalog holds one
+	SpecPath string `json:"specPath,omitempty"`
+}
+
+// resource is a response body served by the catalog, with its entity tag.
+type resource struct {
+	body []byte
+	etag string
+}
+
+// newResource returns a resource for body, tagged with a digest of its content.
+func newResource(body []byte) *resource {
+	sum := sha256.Sum256(body)
+	return &resource{body: body, etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+}
+
+// aggregatorContent is what the catalog holds for one aggregator.
+type aggregatorContent struct {
+	apis   []Published
+	specs  map[types.NamespacedName]*resource
+	merged *resource
+}
+
+// Catalog holds the APIs published by the aggregators. It is safe for concurrent use.
+type Catalog struct {
+	mu          sync.RWMutex
+	aggregators map[types.NamespacedName]*aggregatorContent
+	index       *resource
+}
+
+// New returns an empty catalog.
+func New() *Catalog {
+	c := &Catalog{aggregators: map[types.NamespacedName]*aggregatorContent{}}
+	c.index = c.buildIndex()
+	return c
+}
+
+// Set replaces the APIs and the merged document published by an aggregator. An API whose spec could
+// not be collected keeps the spec published before, so clients keep getting the last known good document,
+// without the operations its current allowed methods no longer allow.
+func (c *Catalog) Set(aggregator types.NamespacedName, entries []Published, merged []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.aggregators[aggregator]
+	content := &aggregatorContent{specs: map[types.NamespacedName]*resource{}}
+	for _, entry := range entries {
+		key := types.NamespacedName{Namespace: entry.Info.Namespace, Name: entry.Info.Name}
+		switch {
+		case entry.Spec != nil:
+			spec := newResource(entry.Spec)
+			if previous != nil && previous.specs[key] != nil && previous.specs[key].etag == spec.etag {
+				spec = previous.specs[key]
+			}
+			content.specs[key] = spec
+		case previous != nil && previous.specs[key] != nil:
+			if spec := pruneResource(previous.specs[key], entry.Info.AllowedMethods); spec != nil {
+				content.specs[key] = spec
+			}
+		}
+		content.apis = append(content.apis, entry)
+	}
+	if merged != nil {
+		content.merged = newResource(merged)
+	}
+
+	c.aggregators[aggregator] = content
+	c.index = c.buildIndex()
+}
+
+// pruneResource returns a previously published spec without the operations that allowedMethods does not
+// allow. A spec that cannot be parsed is dropped rather than served unpruned.
+func pruneResource(spec *resource, allowedMethods []string) *resource {
+	if len(allowedMethods) == 0 {
+		return spec
+	}
+	document, err := openapi.Parse(spec.body)
+	if err != nil {
+		return nil
+	}
+	if openapi.PruneOperations(document, allowedMethods) == 0 {
+		return spec
+	}
+	body, err := document.MarshalJSON()
+	if err != nil {
+		return nil
+	}
+	return newResource(body)
+}
+
+// Delete removes everything published by an aggregator.
+func (c *Catalog) Delete(aggregator types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.aggregators[aggregator]; !ok {
+		return
+	}
+	delete(c.aggregators, aggregator)
+	c.index = c.buildIndex()
+}
+
+// APIs returns the APIs of all aggregators, ordered by aggregator and then by API.
+func (c *Catalog) APIs() []API {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.apis()
+}
+
+// apis lists the APIs of all aggregators; the caller holds the lock.
+func (c *Catalog) apis() []API {
+	apis := []API{}
+	for _, aggregator := range c.sortedAggregators() {
+		content := c.aggregators[aggregator]
+		for _, entry := range content.apis {
+			api := API{APIInfo: entry.Info, Aggregator: aggregator.String()}
+			if content.specs[types.NamespacedName{Namespace: entry.Info.Namespace, Name: entry.Info.Name}] != nil {
+				api.SpecPath = specPath(entry.Info.Namespace, entry.Info.Name)
+			}
+			apis = append(apis, api)
+		}
+	}
+	sort.SliceStable(apis, func(i, j int) bool {
+		if apis[i].Aggregator != apis[j].Aggregator {
+			return apis[i].Aggregator < apis[j].Aggregator
+		}
+		if apis[i].Namespace != apis[j].Namespace {
+			return apis[i].Namespace < apis[j].Namespace
+		}
+		return apis[i].Name < apis[j].Name
+	})
+	return apis
+}
+
+// buildIndex renders the API list served at /apis; the caller holds the lock.
+func (c *Catalog) buildIndex() *resource {
+	body, err := json.Marshal(c.apis())
+	if err != nil {
+		// APIInfo only holds plain fields, so this cannot happen.
+		body = []byte("[]")
+	}
+	return newResource(body)
+}
+
+// spec returns the document of an API. When several aggregators collected the API,
+// the document of the first one, by namespace and name, is returned.
+func (c *Catalog) spec(api types.NamespacedName) *resource {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, aggregator := range c.sortedAggregators() {
+		if spec := c.aggregators[aggregator].specs[api]; spec != nil {
+			return spec
+		}
+	}
+	return nil
+}
+
+// merged returns the merged documents of the aggregators that publish one, by aggregator.
+func (c *Catalog) merged() map[types.NamespacedName]*resource {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	merged := map[types.NamespacedName]*resource{}
+	for aggregator, content := range c.aggregators {
+		if content.merged != nil {
+			merged[aggregator] = content.merged
+		}
+	}
+	return merged
+}
+
+// sortedAggregators returns the aggregators ordered by namespace and name; the caller holds the lock.
+func (c *Catalog) sortedAggregators() []types.NamespacedName {
+	aggregators := make([]types.NamespacedName, 0, len(c.aggregators))
+	for aggregator := range c.aggregators {
+		aggregators = append(aggregators, aggregator)
+	}
+	sort.Slice(aggregators, func(i, j int) bool {
+		return aggregators[i].String() < aggregators[j].String()
+	})
+	return aggregators
+}
+
+// specPath returns the path an API's document is served at.
+func specPath(namespace, name string) string {
+	return "/apis/" + namespace + "/" + name
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+)
+
+var _ = Describe("Catalog", func() {
+	var (
+		catalog *Catalog
+		handler http.Handler
+		first   = types.NamespacedName{Namespace: "team-a", Name: "apis"}
+		second  = types.NamespacedName{Namespace: "team-b", Name: "apis"}
+	)
+
+	get := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	entry := func(namespace, name, spec string) Published {
+		e := Published{Info: observabilityv1alpha1.APIInfo{Namespace: namespace, Name: name, URL: "http://" + name}}
+		if spec != "" {
+			e.Spec = []byte(spec)
+		}
+		return e
+	}
+
+	BeforeEach(func() {
+		catalog = New()
+		handler = catalog.Handler()
+	})
+
+	It("lists the APIs of all aggregators", func() {
+		catalog.Set(second, []Published{entry("shop", "orders", `{"openapi":"3.0.0"}`)}, nil)
+		catalog.Set(first, []Published{entry("shop", "users", "")}, nil)
+
+		rec := get("/apis", nil)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var apis []API
+		Expect(json.Unmarshal(rec.Body.Bytes(), &apis)).To(Succeed())
+		Expect(apis).To(HaveLen(2))
+		Expect(apis[0].Aggregator).To(Equal("team-a/apis"))
+		Expect(apis[0].Name).To(Equal("users"))
+		Expect(apis[0].SpecPath).To(BeEmpty())
+		Expect(apis[1].Name).To(Equal("orders"))
+		Expect(apis[1].SpecPath).To(Equal("/apis/shop/orders"))
+	})
+
+	It("serves the document of an API", func() {
+		catalog.Set(first, []Published{entry("shop", "orders", `{"openapi":"3.0.0"}`)}, nil)
+
+		rec := get("/apis/shop/orders", nil)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(`{"openapi":"3.0.0"}`))
+
+		Expect(get("/apis/shop/users", nil).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("answers 304 Not Modified to a matching If-None-Match", func() {
+		catalog.Set(first, []Published{entry("shop", "orders", `{"openapi":"3.0.0"}`)}, nil)
+
+		etag := get("/apis/shop/orders", nil).Header().Get("ETag")
+		Expect(etag).NotTo(BeEmpty())
+
+		rec := get("/apis/shop/orders", map[string]string{"If-None-Match": etag})
+		Expect(rec.Code).To(Equal(http.StatusNotModified))
+		Expect(rec.Body.Len()).To(BeZero())
+
+		catalog.Set(first, []Published{entry("shop", "orders", `{"openapi":"3.1.0"}`)}, nil)
+		rec = get("/apis/shop/orders", map[string]string{"If-None-Match": etag})
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("ETag")).NotTo(Equal(etag))
+	})
+
+	It("changes the ETag of the API list only when the list changes", func() {
+		catalog.Set(first, []Published{entry("shop", "orders", `{"openapi":"3.0.0"}`)}, nil)
+		etag := get("/apis", nil).Header().Get("ETag")
+
+		catalog.Set(first, []Published{entry("shop", "orders", `{"openapi":"3.0.0"}`)}, nil)
+		Expect(get("/apis", map[string]string{"If-None-Match": etag}).Code).To(Equal(http.StatusNotModified))
+
+		catalog.Delete(first)
+		Expect(get("/apis", map[string]string{"If-None-Match": etag}).Code).To(Equal(http.StatusOK))
+		Expect(get("/apis", nil).Body.String()).To(Equal("[]"))
+	})
+
+	It("keeps the last document of an API that could not be collected", func() {
+		catalog.Set(first, []Published{entry("shop", "orders", `{"openapi":"3.0.0"}`)}, nil)
+		catalog.Set(first, []Published{entry("shop", "orders", "")}, nil)
+
+		Expect(get("/apis/shop/orders", nil).Body.String()).To(Equal(`{"openapi":"3.0.0"}`))
+
+		catalog.Set(first, nil, nil)
+		Expect(get("/apis/shop/orders", nil).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("prunes the last document of an API with the methods it allows now", func() {
+		const spec = `{"openapi":"3.0.3","info":{"title":"Orders","version":"1.0.0"},"paths":{"/orders":{` +
+			`"get":{"responses":{"200":{"description":"OK"}}},"delete":{"responses":{"204":{"description":"Deleted"}}}}}}`
+		catalog.Set(first, []Published{entry("shop", "orders", spec)}, nil)
+
+		failed := entry("shop", "orders", "")
+		failed.Info.AllowedMethods = []string{"GET"}
+		catalog.Set(first, []Published{failed}, nil)
+
+		body := get("/apis/shop/orders", nil).Body.String()
+		Expect(body).To(ContainSubstring(`"get"`))
+		Expect(body).NotTo(ContainSubstring(`"delete"`))
+	})
+
+	It("serves the merged document", func() {
+		Expect(get("/merged.json", nil).Code).To(Equal(http.StatusNotFound))
+
+		catalog.Set(first, nil, []byte(`{"info":{"title":"a"}}`))
+		Expect(get("/merged.json", nil).Body.String()).To(Equal(`{"info":{"title":"a"}}`))
+
+		catalog.Set(second, nil, []byte(`{"info":{"title":"b"}}`))
+		Expect(get("/merged.json", nil).Code).To(Equal(http.StatusConflict))
+		Expect(get("/merged.json?aggregator=team-b/apis", nil).Body.String()).To(Equal(`{"info":{"title":"b"}}`))
+		Expect(get("/merged.json?aggregator=team-c/apis", nil).Code).To(Equal(http.StatusNotFound))
+		Expect(get("/merged.json?aggregator=apis", nil).Code).To(Equal(http.StatusBadRequest))
+	})
+})
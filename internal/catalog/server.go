@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// readHeaderTimeout bounds how long a client may take to send the request headers.
+	readHeaderTimeout = 10 * time.Second
+
+	// shutdownTimeout bounds how long in-flight requests are given to complete on shutdown.
+	shutdownTimeout = 5 * time.Second
+)
+
+// Handler returns an http.Handler serving the catalog:
+//
+//   - GET /apis lists the APIs of all aggregators
+//   - GET /apis/{namespace}/{name} returns the document of an API
+//   - GET /merged.json returns the merged document; when several aggregators publish one,
+//     the aggregator is selected with ?aggregator=<namespace>/<name>
+//
+// Responses carry an ETag, and requests with a matching If-None-Match get 304 Not Modified.
+func (c *Catalog) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /apis", func(w http.ResponseWriter, r *http.Request) {
+		c.mu.RLock()
+		index := c.index
+		c.mu.RUnlock()
+		serveResource(w, r, index)
+	})
+	mux.HandleFunc("GET /apis/{namespace}/{name}", func(w http.ResponseWriter, r *http.Request) {
+		spec := c.spec(types.NamespacedName{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")})
+		if spec == nil {
+			http.Error(w, "no document collected for this API", http.StatusNotFound)
+			return
+		}
+		serveResource(w, r, spec)
+	})
+	mux.HandleFunc("GET /merged.json", c.serveMerged)
+	return mux
+}
+
+// serveMerged serves the merged document of the requested aggregator, or of the only one publishing one.
+func (c *Catalog) serveMerged(w http.ResponseWriter, r *http.Request) {
+	merged := c.merged()
+	if name := r.URL.Query().Get("aggregator"); name != "" {
+		namespace, name, ok := strings.Cut(name, "/")
+		if !ok {
+			http.Error(w, "aggregator must be given as <namespace>/<name>", http.StatusBadRequest)
+			return
+		}
+		doc := merged[types.NamespacedName{Namespace: namespace, Name: name}]
+		if doc == nil {
+			http.Error(w, "aggregator publishes no merged document", http.StatusNotFound)
+			return
+		}
+		serveResource(w, r, doc)
+		return
+	}
+
+	switch len(merged) {
+	case 0:
+		http.Error(w, "no aggregator publishes a merged document", http.StatusNotFound)
+	case 1:
+		for _, doc := range merged {
+			serveResource(w, r, doc)
+		}
+	default:
+		http.Error(w, fmt.Sprintf("%d aggregators publish a merged document; select one with ?aggregator=<namespace>/<name>", len(merged)),
+			http.StatusConflict)
+	}
+}
+
+// serveResource writes a JSON resource, honouring If-None-Match and HEAD requests.
+func serveResource(w http.ResponseWriter, r *http.Request, res *resource) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", res.etag)
+	// Clients may cache the body but must revalidate it, which is cheap thanks to the ETag.
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(res.body))
+}
+
+// Server is a manager.Runnable serving a catalog over HTTP.
+type Server struct {
+	// Addr is the address the server listens on
+	Addr string
+
+	// Catalog is the catalog served
+	Catalog *Catalog
+}
+
+var _ manager.LeaderElectionRunnable = &Server{}
+
+// Start serves the catalog until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("catalog")
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Catalog.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("Serving API catalog", "address", s.Addr)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// NeedLeaderElection returns true: the catalog is filled by the aggregator reconciles,
+// which only run on the leader.
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
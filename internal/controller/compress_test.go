@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+var _ = Describe("Compressed specs", func() {
+	const specJSON = `{"openapi":"3.0.3","info":{"title":"Orders","version":"1.0.0"},"paths":{}}`
+
+	var (
+		instance *observabilityv1alpha1.OpenAPIAggregator
+		api      *collectedAPI
+	)
+
+	BeforeEach(func() {
+		instance = &observabilityv1alpha1.OpenAPIAggregator{
+			TypeMeta:   metav1.TypeMeta{APIVersion: observabilityv1alpha1.GroupVersion.String(), Kind: "OpenAPIAggregator"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis", UID: types.UID("aggregator-uid")},
+		}
+		instance.Spec.Output.StoreSpecs = true
+		instance.Spec.Output.CompressSpecs = true
+
+		document, err := openapi.Parse([]byte(specJSON))
+		Expect(err).NotTo(HaveOccurred())
+		api = &collectedAPI{Info: observabilityv1alpha1.APIInfo{Name: "orders", Namespace: "team-a"}, Document: document}
+	})
+
+	It("round-trips data through gzip deterministically", func() {
+		compressed, err := gzipBytes([]byte(specJSON))
+		Expect(err).NotTo(HaveOccurred())
+		again, err := gzipBytes([]byte(specJSON))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(Equal(compressed))
+
+		decompressed, err := gunzipBytes(compressed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(decompressed)).To(Equal(specJSON))
+	})
+
+	It("rejects data that is not gzip-compressed", func() {
+		_, err := gunzipBytes([]byte(specJSON))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("checksums the uncompressed document", func() {
+		Expect(specChecksum([]byte("abc"))).To(Equal("sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"))
+	})
+
+	It("stores the document compressed in binaryData", func() {
+		entry, err := apiOutputEntry(instance, api, outputEntry{})
+		Expect(err).NotTo(HaveOccurred())
+
+		stored := decodeConfigMapEntry(entry.Data["team-a.orders"])
+		Expect(stored.Spec).To(BeEmpty())
+		Expect(stored.SpecEncoding).To(Equal(gzipEncoding))
+		Expect(stored.SpecKey).To(Equal("team-a.orders.gz"))
+
+		spec, err := gunzipBytes(entry.BinaryData[stored.SpecKey])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(specChecksum(spec)).To(Equal(stored.SpecChecksum))
+	})
+
+	It("reuses the previous compressed bytes while the checksum is unchanged", func() {
+		first, err := apiOutputEntry(instance, api, outputEntry{})
+		Expect(err).NotTo(HaveOccurred())
+		// Marks the previous bytes, so that reusing them can be told apart from compressing again.
+		previous := outputEntry{Data: first.Data, BinaryData: map[string][]byte{"team-a.orders.gz": []byte("previous")}}
+
+		second, err := apiOutputEntry(instance, api, previous)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.BinaryData["team-a.orders.gz"]).To(Equal([]byte("previous")))
+
+		changed, err := openapi.Parse([]byte(`{"openapi":"3.0.3","info":{"title":"Orders","version":"2.0.0"},"paths":{}}`))
+		Expect(err).NotTo(HaveOccurred())
+		api.Document = changed
+		third, err := apiOutputEntry(instance, api, previous)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(third.BinaryData["team-a.orders.gz"]).NotTo(Equal([]byte("previous")))
+	})
+
+	It("does not rewrite a ConfigMap whose binaryData is unchanged", func() {
+		r := &OpenAPIAggregatorReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+			Recorder: record.NewFakeRecorder(100),
+		}
+		entry, err := apiOutputEntry(instance, api, outputEntry{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.writeConfigMap(ctx, instance, "apis-specs", entry.Data, entry.BinaryData, nil)).To(Succeed())
+		written := &corev1.ConfigMap{}
+		Expect(r.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "apis-specs"}, written)).To(Succeed())
+
+		again, err := apiOutputEntry(instance, api, outputEntry{Data: written.Data, BinaryData: written.BinaryData})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.writeConfigMap(ctx, instance, "apis-specs", again.Data, again.BinaryData, nil)).To(Succeed())
+		unchanged := &corev1.ConfigMap{}
+		Expect(r.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "apis-specs"}, unchanged)).To(Succeed())
+		Expect(unchanged.ResourceVersion).To(Equal(written.ResourceVersion))
+	})
+})
@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+)
+
+const (
+	// eventRepeatInterval is how long an event identical to one already recorded for the same object is dropped.
+	eventRepeatInterval = 5 * time.Minute
+
+	// maxTrackedEvents bounds the number of recent events remembered. Expired events are forgotten first,
+	// then the oldest ones.
+	maxTrackedEvents = 1024
+)
+
+// throttledRecorder is an EventRecorder that drops events identical to one recorded for the same object
+// within the repeat interval, so periodic reconciles do not flood the event stream.
+type throttledRecorder struct {
+	recorder record.EventRecorder
+	interval time.Duration
+
+	// now returns the current time; time.Now unless replaced in tests
+	now func() time.Time
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+var _ record.EventRecorder = &throttledRecorder{}
+
+// newThrottledRecorder wraps recorder so that repeated events are recorded at most once per interval.
+func newThrottledRecorder(recorder record.EventRecorder, interval time.Duration) *throttledRecorder {
+	return &throttledRecorder{
+		recorder: recorder,
+		interval: interval,
+		now:      time.Now,
+		last:     map[string]time.Time{},
+	}
+}
+
+// Event records an event unless an identical one was recorded for the object within the interval.
+func (t *throttledRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if t.allow(object, eventtype, reason, message) {
+		t.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+// Eventf is like Event, but formats the message with fmt.Sprintf.
+func (t *throttledRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	t.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf is like Eventf, but attaches annotations to the event.
+func (t *throttledRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if t.allow(object, eventtype, reason, message) {
+		t.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
+
+// allow reports whether an event may be recorded, remembering it if so.
+func (t *throttledRecorder) allow(object runtime.Object, eventtype, reason, message string) bool {
+	key := eventtype + "/" + reason + "/" + message
+	if accessor, err := meta.Accessor(object); err == nil {
+		key = fmt.Sprintf("%s/%s/%s/%s", accessor.GetUID(), accessor.GetNamespace(), accessor.GetName(), key)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if last, ok := t.last[key]; ok && now.Sub(last) < t.interval {
+		return false
+	}
+	if len(t.last) >= maxTrackedEvents {
+		t.evict(now)
+	}
+	t.last[key] = now
+	return true
+}
+
+// evict forgets the expired events, or the oldest one if none has expired, to make room for another.
+func (t *throttledRecorder) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for k, last := range t.last {
+		if now.Sub(last) >= t.interval {
+			delete(t.last, k)
+			continue
+		}
+		if oldestKey == "" || last.Before(oldest) {
+			oldestKey, oldest = k, last
+		}
+	}
+	if len(t.last) >= maxTrackedEvents {
+		delete(t.last, oldestKey)
+	}
+}
+
+// recordAPIEvents records events for APIs added to or removed from an aggregator since its last status,
+// for specs that changed, and for specs that could not be collected. Events concerning an API are also
+// recorded on its Service while the Service exists and was listed.
+func (r *OpenAPIAggregatorReconciler) recordAPIEvents(instance *observabilityv1alpha1.OpenAPIAggregator,
+	services corev1.ServiceList, apis []collectedAPI) {
+	previous := make(map[string]bool, len(instance.Status.CollectedAPIs))
+	for _, api := range instance.Status.CollectedAPIs {
+		previous[apiKey(api)] = true
+	}
+	current := make(map[string]bool, len(apis))
+
+	digests := r.swapSpecDigests(types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, apis)
+	for _, api := range apis {
+		key := apiKey(api.Info)
+		current[key] = true
+		if !previous[key] {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "APIAdded", "Discovered API %s at %s", key, api.Info.URL)
+			if api.Service != nil {
+				r.Recorder.Eventf(api.Service, corev1.EventTypeNormal, "APIAdded", "Collected by OpenAPIAggregator %s/%s", instance.Namespace, instance.Name)
+			}
+		}
+		if api.Info.Error != "" {
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "SpecFetchFailed", "Failed to collect spec of %s: %s", key, api.Info.Error)
+			if api.Service != nil {
+				r.Recorder.Eventf(api.Service, corev1.EventTypeWarning, "SpecFetchFailed", "Failed to collect spec from %s: %s", api.Info.URL, api.Info.Error)
+			}
+		} else if digest, ok := digests[key]; ok && digest != collectedSpecChecksum(api) {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "SpecChanged", "Spec of %s changed (version %q)", key, api.Info.Version)
+			if api.Service != nil {
+				r.Recorder.Eventf(api.Service, corev1.EventTypeNormal, "SpecChanged", "Spec changed (version %q)", api.Info.Version)
+			}
+		}
+	}
+
+	for key := range previous {
+		if current[key] {
+			continue
+		}
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "APIRemoved", "API %s is no longer collected", key)
+		for i := range services.Items {
+			if svc := &services.Items[i]; svc.Namespace+"."+svc.Name == key {
+				r.Recorder.Eventf(svc, corev1.EventTypeNormal, "APIRemoved", "No longer collected by OpenAPIAggregator %s/%s", instance.Namespace, instance.Name)
+			}
+		}
+	}
+}
+
+// swapSpecDigests stores the digests of the specs collected for an aggregator and returns the previous ones.
+// APIs whose spec could not be collected keep their previous digest.
+func (r *OpenAPIAggregatorReconciler) swapSpecDigests(aggregator types.NamespacedName, apis []collectedAPI) map[string]string {
+	r.specDigestsMu.Lock()
+	defer r.specDigestsMu.Unlock()
+
+	previous := r.specDigests[aggregator]
+	next := make(map[string]string, len(apis))
+	for _, api := range apis {
+		key := apiKey(api.Info)
+		if digest := collectedSpecChecksum(api); digest != "" {
+			next[key] = digest
+		} else if digest, ok := previous[key]; ok {
+			next[key] = digest
+		}
+	}
+	if r.specDigests == nil {
+		r.specDigests = map[types.NamespacedName]map[string]string{}
+	}
+	r.specDigests[aggregator] = next
+	return previous
+}
+
+// forgetSpecDigests drops the spec digests of a deleted aggregator.
+func (r *OpenAPIAggregatorReconciler) forgetSpecDigests(aggregator types.NamespacedName) {
+	r.specDigestsMu.Lock()
+	defer r.specDigestsMu.Unlock()
+	delete(r.specDigests, aggregator)
+}
+
+// collectedSpecChecksum returns the checksum of an API's collected spec, or "" if none was collected.
+func collectedSpecChecksum(api collectedAPI) string {
+	if api.Document == nil || api.Info.Error != "" {
+		return ""
+	}
+	data, err := api.Document.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return specChecksum(data)
+}
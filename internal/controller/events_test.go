@@ -0,0 +1,209 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+var _ = Describe("throttledRecorder", func() {
+	var (
+		fakeRecorder *record.FakeRecorder
+		throttled    *throttledRecorder
+		now          time.Time
+		svc          *corev1.Service
+	)
+
+	BeforeEach(func() {
+		fakeRecorder = record.NewFakeRecorder(2 * maxTrackedEvents)
+		throttled = newThrottledRecorder(fakeRecorder, time.Minute)
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		throttled.now = func() time.Time { return now }
+		svc = newService("team-a", "orders", nil, nil)
+		svc.UID = types.UID("orders-uid")
+	})
+
+	It("drops an identical event within the interval", func() {
+		throttled.Eventf(svc, corev1.EventTypeWarning, "SpecFetchFailed", "Failed to collect spec: %s", "timeout")
+		now = now.Add(30 * time.Second)
+		throttled.Eventf(svc, corev1.EventTypeWarning, "SpecFetchFailed", "Failed to collect spec: %s", "timeout")
+
+		Expect(fakeRecorder.Events).To(HaveLen(1))
+		Expect(<-fakeRecorder.Events).To(Equal("Warning SpecFetchFailed Failed to collect spec: timeout"))
+	})
+
+	It("records events that differ in object, reason or message", func() {
+		other := newService("team-a", "payments", nil, nil)
+		throttled.Event(svc, corev1.EventTypeNormal, "APIAdded", "added")
+		throttled.Event(other, corev1.EventTypeNormal, "APIAdded", "added")
+		throttled.Event(svc, corev1.EventTypeNormal, "SpecChanged", "added")
+		throttled.Event(svc, corev1.EventTypeNormal, "APIAdded", "added again")
+
+		Expect(fakeRecorder.Events).To(HaveLen(4))
+	})
+
+	It("records an identical event again after the interval", func() {
+		throttled.Event(svc, corev1.EventTypeNormal, "APIAdded", "added")
+		now = now.Add(time.Minute)
+		throttled.Event(svc, corev1.EventTypeNormal, "APIAdded", "added")
+
+		Expect(fakeRecorder.Events).To(HaveLen(2))
+	})
+
+	It("forgets the oldest event when every tracked one is recent", func() {
+		for i := 0; i < maxTrackedEvents; i++ {
+			throttled.Eventf(svc, corev1.EventTypeNormal, "APIAdded", "event %d", i)
+			now = now.Add(time.Millisecond)
+		}
+		throttled.Event(svc, corev1.EventTypeNormal, "APIAdded", "one more")
+		Expect(throttled.last).To(HaveLen(maxTrackedEvents))
+
+		// The oldest event is no longer throttled; the newest ones still are.
+		throttled.Eventf(svc, corev1.EventTypeNormal, "APIAdded", "event %d", 0)
+		throttled.Eventf(svc, corev1.EventTypeNormal, "APIAdded", "event %d", maxTrackedEvents-1)
+		Expect(fakeRecorder.Events).To(HaveLen(maxTrackedEvents + 2))
+	})
+
+	It("forgets expired events before recent ones", func() {
+		for i := 0; i < maxTrackedEvents; i++ {
+			throttled.Event(svc, corev1.EventTypeNormal, "APIAdded", fmt.Sprintf("event %d", i))
+		}
+		now = now.Add(time.Minute)
+		throttled.Event(svc, corev1.EventTypeNormal, "APIAdded", "one more")
+		Expect(throttled.last).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("recordAPIEvents", func() {
+	var (
+		fakeRecorder *record.FakeRecorder
+		r            *OpenAPIAggregatorReconciler
+		instance     *observabilityv1alpha1.OpenAPIAggregator
+		svc          *corev1.Service
+		services     corev1.ServiceList
+	)
+
+	// events drains the recorded events.
+	events := func() []string {
+		var recorded []string
+		for len(fakeRecorder.Events) > 0 {
+			recorded = append(recorded, <-fakeRecorder.Events)
+		}
+		return recorded
+	}
+
+	// ordersAPI returns the API collected from svc, with a spec of the given version or an error.
+	ordersAPI := func(version, apiError string) collectedAPI {
+		api := collectedAPI{
+			Info:    observabilityv1alpha1.APIInfo{Namespace: "team-a", Name: "orders", URL: "http://orders", Version: version, Error: apiError},
+			Service: svc,
+		}
+		if apiError == "" {
+			document, err := openapi.Parse([]byte(`{"openapi":"3.0.3","info":{"title":"Orders","version":"` + version + `"},"paths":{}}`))
+			Expect(err).NotTo(HaveOccurred())
+			api.Document = document
+		}
+		return api
+	}
+
+	// reconcile records the events of a reconcile collecting apis, and keeps them in the status as Reconcile does.
+	reconcile := func(apis ...collectedAPI) []string {
+		r.recordAPIEvents(instance, services, apis)
+		instance.Status.CollectedAPIs = apiInfos(apis)
+		return events()
+	}
+
+	BeforeEach(func() {
+		fakeRecorder = record.NewFakeRecorder(100)
+		r = &OpenAPIAggregatorReconciler{Recorder: fakeRecorder}
+		instance = &observabilityv1alpha1.OpenAPIAggregator{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis"}}
+		svc = newService("team-a", "orders", nil, nil)
+		services = corev1.ServiceList{Items: []corev1.Service{*svc}}
+	})
+
+	It("records added APIs on the aggregator and the Service", func() {
+		Expect(reconcile(ordersAPI("1.0.0", ""))).To(Equal([]string{
+			"Normal APIAdded Discovered API team-a.orders at http://orders",
+			"Normal APIAdded Collected by OpenAPIAggregator team-a/apis",
+		}))
+	})
+
+	It("records nothing while the spec is unchanged", func() {
+		reconcile(ordersAPI("1.0.0", ""))
+		Expect(reconcile(ordersAPI("1.0.0", ""))).To(BeEmpty())
+	})
+
+	It("records changed specs", func() {
+		reconcile(ordersAPI("1.0.0", ""))
+		Expect(reconcile(ordersAPI("1.1.0", ""))).To(Equal([]string{
+			`Normal SpecChanged Spec of team-a.orders changed (version "1.1.0")`,
+			`Normal SpecChanged Spec changed (version "1.1.0")`,
+		}))
+	})
+
+	It("records failed fetches and compares the next spec with the last collected one", func() {
+		reconcile(ordersAPI("1.0.0", ""))
+		Expect(reconcile(ordersAPI("", "connection refused"))).To(Equal([]string{
+			"Warning SpecFetchFailed Failed to collect spec of team-a.orders: connection refused",
+			"Warning SpecFetchFailed Failed to collect spec from http://orders: connection refused",
+		}))
+		Expect(reconcile(ordersAPI("1.0.0", ""))).To(BeEmpty())
+	})
+
+	It("records removed APIs on the aggregator and on the Service while it is listed", func() {
+		reconcile(ordersAPI("1.0.0", ""))
+		Expect(reconcile()).To(Equal([]string{
+			"Normal APIRemoved API team-a.orders is no longer collected",
+			"Normal APIRemoved No longer collected by OpenAPIAggregator team-a/apis",
+		}))
+
+		reconcile(ordersAPI("1.0.0", ""))
+		services = corev1.ServiceList{}
+		Expect(reconcile()).To(Equal([]string{"Normal APIRemoved API team-a.orders is no longer collected"}))
+	})
+
+	It("records a repeated failure once per interval through the throttled recorder", func() {
+		throttled := newThrottledRecorder(fakeRecorder, eventRepeatInterval)
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		throttled.now = func() time.Time { return now }
+		r.Recorder = throttled
+
+		reconcile(ordersAPI("1.0.0", ""))
+		Expect(reconcile(ordersAPI("", "connection refused"))).To(HaveLen(2))
+		now = now.Add(eventRepeatInterval / 2)
+		Expect(reconcile(ordersAPI("", "connection refused"))).To(BeEmpty())
+		now = now.Add(eventRepeatInterval / 2)
+		Expect(reconcile(ordersAPI("", "connection refused"))).To(HaveLen(2))
+	})
+
+	It("records events only on the aggregator for APIs without a Service", func() {
+		api := ordersAPI("1.0.0", "")
+		api.Service = nil
+		Expect(reconcile(api)).To(Equal([]string{"Normal APIAdded Discovered API team-a.orders at http://orders"}))
+	})
+})
@@ -0,0 +1,334 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+const (
+	// historyConfigMapSuffix is appended to the output ConfigMap name to form the name of the history ConfigMap.
+	historyConfigMapSuffix = "-history"
+
+	// maxRecordedChanges bounds the number of changes listed on a revision.
+	maxRecordedChanges = 20
+)
+
+// historyConfigMapName returns the name of the ConfigMap holding the spec revisions of an output ConfigMap.
+func historyConfigMapName(configMapName string) string {
+	return configMapName + historyConfigMapSuffix
+}
+
+// revisionKey returns the binaryData key of a revision's content in the history ConfigMap.
+func revisionKey(key, hash string) string {
+	return key + "." + strings.TrimPrefix(hash, "sha256:")[:12] + ".json" + compressedKeySuffix
+}
+
+// specHistory is the content of a history ConfigMap.
+type specHistory struct {
+	// revisions holds the revisions of each API, newest first
+	revisions map[string][]observabilityv1alpha1.SpecRevision
+
+	// contents holds the compressed content of each revision by key
+	contents map[string][]byte
+}
+
+// recordRevisions adds the collected specs that changed to the aggregator's spec history, drops the
+// revisions beyond spec.output.revisionHistoryLimit, and sets the kept revisions of each API on its info.
+func (r *OpenAPIAggregatorReconciler) recordRevisions(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator, apis []collectedAPI) {
+	logger := log.FromContext(ctx)
+	name := historyConfigMapName(outputConfigMapName(instance))
+	limit := int(instance.Spec.Output.RevisionHistoryLimit)
+	if limit <= 0 {
+		if err := r.deleteControlledConfigMap(ctx, instance, name, "Deleted spec history ConfigMap %s"); err != nil {
+			logger.Error(err, "Failed to delete spec history ConfigMap", "ConfigMap.Name", name)
+		}
+		return
+	}
+
+	history, err := r.readHistory(ctx, instance.Namespace, name)
+	if err != nil {
+		logger.Error(err, "Failed to read spec history ConfigMap", "ConfigMap.Name", name)
+		return
+	}
+
+	now := metav1.Now()
+	next := specHistory{revisions: map[string][]observabilityv1alpha1.SpecRevision{}, contents: map[string][]byte{}}
+	for i := range apis {
+		api := &apis[i]
+		key := apiKey(api.Info)
+		revisions := history.revisions[key]
+		if api.Document != nil && api.Info.Error == "" {
+			previous := revisions
+			if revisions, err = addRevision(history, revisions, *api, now); err != nil {
+				logger.Error(err, "Failed to record spec revision", "api", key)
+			}
+			if len(revisions) > 0 && (len(previous) == 0 || previous[0].Hash != revisions[0].Hash) {
+				r.recordBreakingChanges(instance, *api, revisions[0])
+			}
+		}
+		if len(revisions) > limit {
+			revisions = revisions[:limit]
+		}
+		next.revisions[key] = revisions
+		for _, revision := range revisions {
+			if content, ok := history.contents[revision.Key]; ok {
+				next.contents[revision.Key] = content
+			}
+		}
+	}
+	next.fit(maxConfigMapDataBytes)
+
+	data, binaryData, err := next.configMapData()
+	if err == nil {
+		err = r.writeConfigMap(ctx, instance, name, data, binaryData, nil)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to write spec history ConfigMap", "ConfigMap.Name", name)
+	}
+	for i := range apis {
+		apis[i].Info.Revisions = nil
+		for j, revision := range next.revisions[apiKey(apis[i].Info)] {
+			// Only the breaking changes of the newest revision are listed in the status, to keep it small.
+			// All changes are listed in the history ConfigMap.
+			if j == 0 {
+				revision.Changes = breakingChanges(revision.Changes)
+			} else {
+				revision.Changes = nil
+			}
+			apis[i].Info.Revisions = append(apis[i].Info.Revisions, revision)
+		}
+	}
+}
+
+// addRevision returns the revisions of an API with its collected spec as the newest revision, unless
+// it already is. A revision with the same content further back is moved to the front.
+func addRevision(history specHistory, revisions []observabilityv1alpha1.SpecRevision, api collectedAPI,
+	now metav1.Time) ([]observabilityv1alpha1.SpecRevision, error) {
+	spec, err := api.Document.MarshalJSON()
+	if err != nil {
+		return revisions, err
+	}
+	hash := specChecksum(spec)
+	if len(revisions) > 0 && revisions[0].Hash == hash {
+		return revisions, nil
+	}
+
+	revision := observabilityv1alpha1.SpecRevision{Hash: hash, CollectedAt: now, Key: revisionKey(apiKey(api.Info), hash)}
+	if api.Service != nil {
+		revision.ServiceResourceVersion = api.Service.ResourceVersion
+	}
+	if _, ok := history.contents[revision.Key]; !ok {
+		compressed, err := gzipBytes(spec)
+		if err != nil {
+			return revisions, err
+		}
+		history.contents[revision.Key] = compressed
+	}
+
+	if len(revisions) > 0 {
+		if previous, ok := history.contents[revisions[0].Key]; ok {
+			setRevisionChanges(&revision, previous, api.Document)
+		}
+	}
+
+	updated := []observabilityv1alpha1.SpecRevision{revision}
+	for _, previous := range revisions {
+		if previous.Hash != hash {
+			updated = append(updated, previous)
+		}
+	}
+	return updated, nil
+}
+
+// setRevisionChanges records on a revision the changes from the previous revision's compressed content.
+// Nothing is recorded if the previous content cannot be read.
+func setRevisionChanges(revision *observabilityv1alpha1.SpecRevision, previousContent []byte, current *openapi.Document) {
+	data, err := gunzipBytes(previousContent)
+	if err != nil {
+		return
+	}
+	previous, err := openapi.Parse(data)
+	if err != nil {
+		return
+	}
+
+	changes := openapi.Diff(previous, current)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Breaking && !changes[j].Breaking })
+	for _, change := range changes {
+		if change.Breaking {
+			revision.BreakingChanges++
+		} else {
+			revision.NonBreakingChanges++
+		}
+		if len(revision.Changes) < maxRecordedChanges {
+			revision.Changes = append(revision.Changes, observabilityv1alpha1.SpecChange{
+				Breaking: change.Breaking,
+				Location: change.Location,
+				Message:  change.Message,
+			})
+		}
+	}
+}
+
+// breakingChanges returns the breaking changes among changes.
+func breakingChanges(changes []observabilityv1alpha1.SpecChange) []observabilityv1alpha1.SpecChange {
+	var breaking []observabilityv1alpha1.SpecChange
+	for _, change := range changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// recordBreakingChanges records events on the aggregator and the API's Service when a new revision
+// of the API's spec has breaking changes.
+func (r *OpenAPIAggregatorReconciler) recordBreakingChanges(instance *observabilityv1alpha1.OpenAPIAggregator,
+	api collectedAPI, revision observabilityv1alpha1.SpecRevision) {
+	if revision.BreakingChanges == 0 {
+		return
+	}
+	var examples []string
+	for _, change := range revision.Changes {
+		if change.Breaking && len(examples) < 3 {
+			examples = append(examples, change.Location+": "+change.Message)
+		}
+	}
+	summary := strings.Join(examples, "; ")
+	r.Recorder.Eventf(instance, corev1.EventTypeWarning, "BreakingSpecChange", "Spec of %s has %d breaking changes: %s",
+		apiKey(api.Info), revision.BreakingChanges, summary)
+	if api.Service != nil {
+		r.Recorder.Eventf(api.Service, corev1.EventTypeWarning, "BreakingSpecChange", "Spec has %d breaking changes: %s",
+			revision.BreakingChanges, summary)
+	}
+}
+
+// setBreakingChangesCondition reports on the status whether the latest revision of any API has breaking
+// changes. The condition is only set while the spec history is enabled.
+func setBreakingChangesCondition(status *observabilityv1alpha1.OpenAPIAggregatorStatus, instance *observabilityv1alpha1.OpenAPIAggregator) {
+	if instance.Spec.Output.RevisionHistoryLimit <= 0 {
+		apimeta.RemoveStatusCondition(&status.Conditions, BreakingChangesCondition)
+		return
+	}
+	var breaking []string
+	for _, api := range status.CollectedAPIs {
+		if len(api.Revisions) > 0 && api.Revisions[0].BreakingChanges > 0 {
+			breaking = append(breaking, apiKey(api))
+		}
+	}
+	if len(breaking) == 0 {
+		setCondition(status, instance.Generation, BreakingChangesCondition, metav1.ConditionFalse, "NoBreakingChanges",
+			"No API's latest spec revision has breaking changes")
+		return
+	}
+	setCondition(status, instance.Generation, BreakingChangesCondition, metav1.ConditionTrue, "BreakingChangesDetected",
+		fmt.Sprintf("The latest spec revision of %d APIs has breaking changes: %s", len(breaking), strings.Join(breaking, ", ")))
+}
+
+// fit drops the oldest revisions of the APIs with the most revisions until the history takes at most
+// limit bytes. The newest revision of each API is always kept.
+func (h specHistory) fit(limit int) {
+	for h.size() > limit {
+		longest := ""
+		for key, revisions := range h.revisions {
+			if len(revisions) > 1 && (longest == "" || len(revisions) > len(h.revisions[longest]) ||
+				len(revisions) == len(h.revisions[longest]) && key < longest) {
+				longest = key
+			}
+		}
+		if longest == "" {
+			return
+		}
+		revisions := h.revisions[longest]
+		delete(h.contents, revisions[len(revisions)-1].Key)
+		h.revisions[longest] = revisions[:len(revisions)-1]
+	}
+}
+
+// size approximates the number of bytes the history takes in a ConfigMap.
+func (h specHistory) size() int {
+	size := 0
+	for key, revisions := range h.revisions {
+		size += len(key)
+		for _, revision := range revisions {
+			// Each revision takes about 200 bytes of JSON, plus its changes.
+			size += 200
+			for _, change := range revision.Changes {
+				size += 50 + len(change.Location) + len(change.Message)
+			}
+		}
+	}
+	for key, content := range h.contents {
+		size += len(key) + len(content)
+	}
+	return size
+}
+
+// configMapData returns the data and binaryData of the history ConfigMap.
+func (h specHistory) configMapData() (map[string]string, map[string][]byte, error) {
+	data := make(map[string]string, len(h.revisions))
+	for key, revisions := range h.revisions {
+		if len(revisions) == 0 {
+			continue
+		}
+		revisionsJSON, err := json.Marshal(revisions)
+		if err != nil {
+			return nil, nil, err
+		}
+		data[key] = string(revisionsJSON)
+	}
+	return data, h.contents, nil
+}
+
+// readHistory returns the content of a history ConfigMap, or an empty history if it does not exist.
+func (r *OpenAPIAggregatorReconciler) readHistory(ctx context.Context, namespace, name string) (specHistory, error) {
+	history := specHistory{revisions: map[string][]observabilityv1alpha1.SpecRevision{}, contents: map[string][]byte{}}
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return history, nil
+		}
+		return history, err
+	}
+	for key, value := range cm.Data {
+		var revisions []observabilityv1alpha1.SpecRevision
+		if err := json.Unmarshal([]byte(value), &revisions); err != nil {
+			// A corrupt entry only loses the history of that API.
+			continue
+		}
+		history.revisions[key] = revisions
+	}
+	for key, content := range cm.BinaryData {
+		history.contents[key] = content
+	}
+	return history, nil
+}
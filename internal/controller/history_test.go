@@ -0,0 +1,314 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+// specWithPaths returns a document with an empty GET operation on each path.
+func specWithPaths(paths ...string) *openapi.Document {
+	items := make([]string, 0, len(paths))
+	for _, path := range paths {
+		items = append(items, fmt.Sprintf(`%q:{"get":{"responses":{"200":{"description":"OK"}}}}`, path))
+	}
+	document, err := openapi.Parse([]byte(`{"openapi":"3.0.3","info":{"title":"Orders","version":"1.0.0"},"paths":{` +
+		strings.Join(items, ",") + `}}`))
+	Expect(err).NotTo(HaveOccurred())
+	return document
+}
+
+// gzippedSpec returns the compressed JSON of a document, as stored in the history ConfigMap.
+func gzippedSpec(document *openapi.Document) []byte {
+	spec, err := document.MarshalJSON()
+	Expect(err).NotTo(HaveOccurred())
+	compressed, err := gzipBytes(spec)
+	Expect(err).NotTo(HaveOccurred())
+	return compressed
+}
+
+var _ = Describe("Spec history", func() {
+	var history specHistory
+
+	BeforeEach(func() {
+		history = specHistory{revisions: map[string][]observabilityv1alpha1.SpecRevision{}, contents: map[string][]byte{}}
+	})
+
+	Describe("addRevision", func() {
+		var api collectedAPI
+
+		BeforeEach(func() {
+			api = collectedAPI{
+				Info:    observabilityv1alpha1.APIInfo{Name: "orders", Namespace: "team-a"},
+				Service: &corev1.Service{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "42"}},
+			}
+		})
+
+		It("adds the collected spec as the newest revision and stores its content", func() {
+			api.Document = specWithPaths("/orders")
+			now := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+			revisions, err := addRevision(history, nil, api, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revisions).To(HaveLen(1))
+
+			spec, err := api.Document.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+			revision := revisions[0]
+			Expect(revision.Hash).To(Equal(specChecksum(spec)))
+			Expect(revision.Key).To(Equal(revisionKey("team-a.orders", revision.Hash)))
+			Expect(revision.CollectedAt).To(Equal(now))
+			Expect(revision.ServiceResourceVersion).To(Equal("42"))
+			Expect(revision.BreakingChanges).To(BeZero())
+
+			content, err := gunzipBytes(history.contents[revision.Key])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(spec))
+		})
+
+		It("keeps the revisions when the spec is unchanged", func() {
+			api.Document = specWithPaths("/orders")
+			revisions, err := addRevision(history, nil, api, metav1.Now())
+			Expect(err).NotTo(HaveOccurred())
+
+			again, err := addRevision(history, revisions, api, metav1.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again).To(Equal(revisions))
+		})
+
+		It("moves an earlier revision with the same content back to the front", func() {
+			api.Document = specWithPaths("/orders", "/refunds")
+			revisions, err := addRevision(history, nil, api, metav1.Now())
+			Expect(err).NotTo(HaveOccurred())
+			original := revisions[0].Hash
+
+			api.Document = specWithPaths("/orders")
+			revisions, err = addRevision(history, revisions, api, metav1.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revisions).To(HaveLen(2))
+			Expect(revisions[0].BreakingChanges).To(Equal(int32(1)))
+
+			api.Document = specWithPaths("/orders", "/refunds")
+			revisions, err = addRevision(history, revisions, api, metav1.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revisions).To(HaveLen(2))
+			Expect(revisions[0].Hash).To(Equal(original))
+			Expect(revisions[0].BreakingChanges).To(BeZero())
+			Expect(revisions[0].NonBreakingChanges).To(Equal(int32(1)))
+			Expect(revisions[1].Hash).NotTo(Equal(original))
+		})
+	})
+
+	Describe("recordRevisions", func() {
+		var (
+			r        *OpenAPIAggregatorReconciler
+			instance *observabilityv1alpha1.OpenAPIAggregator
+		)
+
+		// collect records a revision of the orders API with the given paths and returns its info.
+		collect := func(paths ...string) observabilityv1alpha1.APIInfo {
+			apis := []collectedAPI{{
+				Info:     observabilityv1alpha1.APIInfo{Name: "orders", Namespace: "team-a"},
+				Document: specWithPaths(paths...),
+			}}
+			r.recordRevisions(ctx, instance, apis)
+			return apis[0].Info
+		}
+
+		BeforeEach(func() {
+			instance = &observabilityv1alpha1.OpenAPIAggregator{
+				TypeMeta:   metav1.TypeMeta{APIVersion: observabilityv1alpha1.GroupVersion.String(), Kind: "OpenAPIAggregator"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis", UID: types.UID("aggregator-uid")},
+			}
+			instance.Spec.Output.RevisionHistoryLimit = 2
+			r = &OpenAPIAggregatorReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+		})
+
+		It("lists the kept revisions newest first, up to the limit", func() {
+			first := collect("/orders")
+			Expect(first.Revisions).To(HaveLen(1))
+			second := collect("/orders", "/refunds")
+			third := collect("/orders", "/refunds", "/returns")
+
+			Expect(third.Revisions).To(HaveLen(2))
+			Expect(third.Revisions[0].Hash).NotTo(Equal(second.Revisions[0].Hash))
+			Expect(third.Revisions[1].Hash).To(Equal(second.Revisions[0].Hash))
+			Expect(third.Revisions[0].NonBreakingChanges).To(Equal(int32(1)))
+
+			history, err := r.readHistory(ctx, "team-a", "apis-specs-history")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history.revisions["team-a.orders"]).To(HaveLen(2))
+			Expect(history.revisions["team-a.orders"][0].Changes).To(HaveLen(1))
+		})
+
+		It("lists the breaking changes of the newest revision only", func() {
+			collect("/orders", "/refunds")
+			info := collect("/orders", "/returns")
+
+			Expect(info.Revisions).To(HaveLen(2))
+			Expect(info.Revisions[0].BreakingChanges).To(Equal(int32(1)))
+			Expect(info.Revisions[0].NonBreakingChanges).To(Equal(int32(1)))
+			Expect(info.Revisions[0].Changes).To(Equal([]observabilityv1alpha1.SpecChange{
+				{Breaking: true, Location: "/refunds", Message: "path removed"},
+			}))
+			Expect(info.Revisions[1].Changes).To(BeEmpty())
+		})
+
+		It("lists no revisions when the history is disabled", func() {
+			collect("/orders")
+			instance.Spec.Output.RevisionHistoryLimit = 0
+			Expect(collect("/orders", "/refunds").Revisions).To(BeEmpty())
+		})
+	})
+
+	Describe("setRevisionChanges", func() {
+		It("counts the changes and lists the breaking ones first", func() {
+			revision := observabilityv1alpha1.SpecRevision{}
+			setRevisionChanges(&revision, gzippedSpec(specWithPaths("/orders")), specWithPaths("/refunds"))
+
+			Expect(revision.BreakingChanges).To(Equal(int32(1)))
+			Expect(revision.NonBreakingChanges).To(Equal(int32(1)))
+			Expect(revision.Changes).To(Equal([]observabilityv1alpha1.SpecChange{
+				{Breaking: true, Location: "/orders", Message: "path removed"},
+				{Breaking: false, Location: "/refunds", Message: "path added"},
+			}))
+		})
+
+		It("lists at most maxRecordedChanges changes but counts all of them", func() {
+			var paths []string
+			for i := 0; i < maxRecordedChanges+5; i++ {
+				paths = append(paths, fmt.Sprintf("/orders/%02d", i))
+			}
+			revision := observabilityv1alpha1.SpecRevision{}
+			setRevisionChanges(&revision, gzippedSpec(specWithPaths(paths...)), specWithPaths("/refunds"))
+
+			Expect(revision.BreakingChanges).To(Equal(int32(maxRecordedChanges + 5)))
+			Expect(revision.NonBreakingChanges).To(Equal(int32(1)))
+			Expect(revision.Changes).To(HaveLen(maxRecordedChanges))
+			for _, change := range revision.Changes {
+				Expect(change.Breaking).To(BeTrue())
+			}
+		})
+
+		It("records nothing when the previous content cannot be read", func() {
+			revision := observabilityv1alpha1.SpecRevision{}
+			setRevisionChanges(&revision, []byte("not gzip"), specWithPaths("/orders"))
+			Expect(revision).To(Equal(observabilityv1alpha1.SpecRevision{}))
+		})
+	})
+
+	Describe("fit", func() {
+		BeforeEach(func() {
+			for _, key := range []string{"team-a.orders", "team-a.refunds"} {
+				count := 3
+				if key == "team-a.refunds" {
+					count = 1
+				}
+				for i := 0; i < count; i++ {
+					contentKey := fmt.Sprintf("%s.%d.json.gz", key, i)
+					history.revisions[key] = append(history.revisions[key], observabilityv1alpha1.SpecRevision{Key: contentKey})
+					history.contents[contentKey] = make([]byte, 1000)
+				}
+			}
+		})
+
+		It("keeps a history within the limit as is", func() {
+			size := history.size()
+			history.fit(size)
+			Expect(history.size()).To(Equal(size))
+			Expect(history.revisions["team-a.orders"]).To(HaveLen(3))
+		})
+
+		It("drops the oldest revision of the API with the most revisions first", func() {
+			history.fit(history.size() - 1)
+			Expect(history.revisions["team-a.orders"]).To(HaveLen(2))
+			Expect(history.revisions["team-a.refunds"]).To(HaveLen(1))
+			Expect(history.contents).NotTo(HaveKey("team-a.orders.2.json.gz"))
+			Expect(history.contents).To(HaveKey("team-a.orders.1.json.gz"))
+		})
+
+		It("always keeps the newest revision of each API", func() {
+			history.fit(0)
+			Expect(history.revisions["team-a.orders"]).To(ConsistOf(observabilityv1alpha1.SpecRevision{Key: "team-a.orders.0.json.gz"}))
+			Expect(history.revisions["team-a.refunds"]).To(HaveLen(1))
+			Expect(history.contents).To(HaveLen(2))
+		})
+	})
+
+	Describe("setBreakingChangesCondition", func() {
+		var (
+			instance *observabilityv1alpha1.OpenAPIAggregator
+			status   *observabilityv1alpha1.OpenAPIAggregatorStatus
+		)
+
+		BeforeEach(func() {
+			instance = &observabilityv1alpha1.OpenAPIAggregator{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+			instance.Spec.Output.RevisionHistoryLimit = 5
+			status = &observabilityv1alpha1.OpenAPIAggregatorStatus{
+				CollectedAPIs: []observabilityv1alpha1.APIInfo{
+					{Name: "orders", Namespace: "team-a", Revisions: []observabilityv1alpha1.SpecRevision{{NonBreakingChanges: 2}, {BreakingChanges: 1}}},
+					{Name: "refunds", Namespace: "team-a"},
+				},
+			}
+		})
+
+		It("is False when no latest revision has breaking changes", func() {
+			setBreakingChangesCondition(status, instance)
+			condition := apimeta.FindStatusCondition(status.Conditions, BreakingChangesCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NoBreakingChanges"))
+			Expect(condition.ObservedGeneration).To(Equal(int64(3)))
+		})
+
+		It("is True and names the APIs whose latest revision has breaking changes", func() {
+			status.CollectedAPIs[0].Revisions[0].BreakingChanges = 1
+			setBreakingChangesCondition(status, instance)
+			condition := apimeta.FindStatusCondition(status.Conditions, BreakingChangesCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("BreakingChangesDetected"))
+			Expect(condition.Message).To(ContainSubstring("team-a.orders"))
+			Expect(condition.Message).NotTo(ContainSubstring("team-a.refunds"))
+		})
+
+		It("is removed when the spec history is disabled", func() {
+			setBreakingChangesCondition(status, instance)
+			instance.Spec.Output.RevisionHistoryLimit = 0
+			setBreakingChangesCondition(status, instance)
+			Expect(apimeta.FindStatusCondition(status.Conditions, BreakingChangesCondition)).To(BeNil())
+		})
+	})
+})
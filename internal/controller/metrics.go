@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+)
+
+const metricsNamespace = "openapi_aggregator"
+
+var (
+	discoveredServices = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "discovered_services",
+		Help:      "Number of services discovered by an aggregator, by service namespace.",
+	}, []string{"namespace", "aggregator", "service_namespace"})
+
+	specFetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "spec_fetch_duration_seconds",
+		Help:      "Time taken to fetch and parse an OpenAPI spec.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"namespace", "aggregator"})
+
+	specFetchErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "spec_fetch_errors_total",
+		Help:      "Number of OpenAPI specs that could not be collected, by reason.",
+	}, []string{"namespace", "aggregator", "reason"})
+
+	specSizeBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "spec_size_bytes",
+		Help:      "Size of the last fetched OpenAPI spec of an API.",
+	}, []string{"namespace", "aggregator", "api"})
+
+	configMapWrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "configmap_writes_total",
+		Help:      "Number of writes to an aggregator's output ConfigMap, by operation.",
+	}, []string{"namespace", "aggregator", "operation"})
+
+	configMapSizeBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "configmap_size_bytes",
+		Help:      "Size of the data in an aggregator's output ConfigMap.",
+	}, []string{"namespace", "aggregator"})
+
+	apiSyncAge = newSyncAgeCollector()
+)
+
+// transformFailedReason is the spec_fetch_errors_total reason of specs that were fetched but could not be transformed.
+const transformFailedReason = "transform_failed"
+
+func init() {
+	metrics.Registry.MustRegister(
+		discoveredServices,
+		specFetchDuration,
+		specFetchErrors,
+		specSizeBytes,
+		configMapWrites,
+		configMapSizeBytes,
+		apiSyncAge,
+	)
+}
+
+// aggregatorLabels returns the labels identifying an aggregator's series.
+func aggregatorLabels(aggregator types.NamespacedName) prometheus.Labels {
+	return prometheus.Labels{"namespace": aggregator.Namespace, "aggregator": aggregator.Name}
+}
+
+// recordDiscoveryMetrics replaces the per-API series of an aggregator with those of the given status.
+func recordDiscoveryMetrics(aggregator types.NamespacedName, status observabilityv1alpha1.OpenAPIAggregatorStatus, apis []collectedAPI) {
+	discoveredServices.DeletePartialMatch(aggregatorLabels(aggregator))
+	perNamespace := map[string]int{}
+	for _, api := range status.CollectedAPIs {
+		perNamespace[api.Namespace]++
+	}
+	for namespace, count := range perNamespace {
+		discoveredServices.WithLabelValues(aggregator.Namespace, aggregator.Name, namespace).Set(float64(count))
+	}
+
+	specSizeBytes.DeletePartialMatch(aggregatorLabels(aggregator))
+	for _, api := range apis {
+		if api.SpecSize > 0 {
+			specSizeBytes.WithLabelValues(aggregator.Namespace, aggregator.Name, apiKey(api.Info)).Set(float64(api.SpecSize))
+		}
+	}
+
+	apiSyncAge.set(aggregator, status.CollectedAPIs)
+}
+
+// forgetAggregatorMetrics removes every series of a deleted aggregator.
+func forgetAggregatorMetrics(aggregator types.NamespacedName) {
+	labels := aggregatorLabels(aggregator)
+	discoveredServices.DeletePartialMatch(labels)
+	specFetchDuration.DeletePartialMatch(labels)
+	specFetchErrors.DeletePartialMatch(labels)
+	specSizeBytes.DeletePartialMatch(labels)
+	configMapWrites.DeletePartialMatch(labels)
+	configMapSizeBytes.DeletePartialMatch(labels)
+	apiSyncAge.forget(aggregator)
+}
+
+// syncAgeCollector reports the time since each API's spec was last collected successfully,
+// computed when scraped so that it keeps growing while a spec is broken.
+type syncAgeCollector struct {
+	desc *prometheus.Desc
+
+	// now returns the current time; time.Now unless replaced in tests
+	now func() time.Time
+
+	mu   sync.Mutex
+	last map[types.NamespacedName]map[string]time.Time
+}
+
+func newSyncAgeCollector() *syncAgeCollector {
+	return &syncAgeCollector{
+		desc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "api_seconds_since_last_sync"),
+			"Seconds since the spec of an API was last collected successfully.",
+			[]string{"namespace", "aggregator", "api"}, nil),
+		now:  time.Now,
+		last: map[types.NamespacedName]map[string]time.Time{},
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *syncAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *syncAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for aggregator, apis := range c.last {
+		for api, last := range apis {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(last).Seconds(),
+				aggregator.Namespace, aggregator.Name, api)
+		}
+	}
+}
+
+// set replaces the last sync times of an aggregator's APIs. APIs that were never collected are not reported.
+func (c *syncAgeCollector) set(aggregator types.NamespacedName, apis []observabilityv1alpha1.APIInfo) {
+	last := make(map[string]time.Time, len(apis))
+	for _, api := range apis {
+		if updated, err := time.Parse(time.RFC3339, api.LastUpdated); err == nil {
+			last[apiKey(api)] = updated
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last[aggregator] = last
+}
+
+// forget drops the last sync times of a deleted aggregator.
+func (c *syncAgeCollector) forget(aggregator types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.last, aggregator)
+}
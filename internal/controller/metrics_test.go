@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+)
+
+var _ = Describe("Metrics", func() {
+	aggregator := types.NamespacedName{Namespace: "platform", Name: "metrics-test"}
+
+	status := observabilityv1alpha1.OpenAPIAggregatorStatus{CollectedAPIs: []observabilityv1alpha1.APIInfo{
+		{Namespace: "team-a", Name: "orders", LastUpdated: "2025-01-01T00:00:00Z"},
+		{Namespace: "team-a", Name: "refunds", Error: "connection refused"},
+		{Namespace: "team-b", Name: "users", LastUpdated: "2025-01-01T00:01:00Z"},
+	}}
+	apis := []collectedAPI{
+		{Info: status.CollectedAPIs[0], SpecSize: 1200},
+		{Info: status.CollectedAPIs[1]},
+		{Info: status.CollectedAPIs[2], SpecSize: 800},
+	}
+
+	AfterEach(func() {
+		forgetAggregatorMetrics(aggregator)
+	})
+
+	It("records the discovered services by namespace and the spec sizes", func() {
+		recordDiscoveryMetrics(aggregator, status, apis)
+
+		Expect(testutil.CollectAndCompare(discoveredServices, strings.NewReader(`
+# HELP openapi_aggregator_discovered_services Number of services discovered by an aggregator, by service namespace.
+# TYPE openapi_aggregator_discovered_services gauge
+openapi_aggregator_discovered_services{aggregator="metrics-test",namespace="platform",service_namespace="team-a"} 2
+openapi_aggregator_discovered_services{aggregator="metrics-test",namespace="platform",service_namespace="team-b"} 1
+`))).To(Succeed())
+		Expect(testutil.CollectAndCompare(specSizeBytes, strings.NewReader(`
+# HELP openapi_aggregator_spec_size_bytes Size of the last fetched OpenAPI spec of an API.
+# TYPE openapi_aggregator_spec_size_bytes gauge
+openapi_aggregator_spec_size_bytes{aggregator="metrics-test",api="team-a.orders",namespace="platform"} 1200
+openapi_aggregator_spec_size_bytes{aggregator="metrics-test",api="team-b.users",namespace="platform"} 800
+`))).To(Succeed())
+	})
+
+	It("replaces the series of APIs that are no longer collected", func() {
+		recordDiscoveryMetrics(aggregator, status, apis)
+		remaining := observabilityv1alpha1.OpenAPIAggregatorStatus{CollectedAPIs: status.CollectedAPIs[:1]}
+		recordDiscoveryMetrics(aggregator, remaining, apis[:1])
+
+		Expect(testutil.CollectAndCompare(discoveredServices, strings.NewReader(`
+# HELP openapi_aggregator_discovered_services Number of services discovered by an aggregator, by service namespace.
+# TYPE openapi_aggregator_discovered_services gauge
+openapi_aggregator_discovered_services{aggregator="metrics-test",namespace="platform",service_namespace="team-a"} 1
+`))).To(Succeed())
+		Expect(testutil.CollectAndCompare(specSizeBytes, strings.NewReader(`
+# HELP openapi_aggregator_spec_size_bytes Size of the last fetched OpenAPI spec of an API.
+# TYPE openapi_aggregator_spec_size_bytes gauge
+openapi_aggregator_spec_size_bytes{aggregator="metrics-test",api="team-a.orders",namespace="platform"} 1200
+`))).To(Succeed())
+	})
+
+	It("reports the time since each API was last collected when scraped", func() {
+		collector := newSyncAgeCollector()
+		collector.now = func() time.Time { return time.Date(2025, 1, 1, 0, 2, 0, 0, time.UTC) }
+		collector.set(aggregator, status.CollectedAPIs)
+
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP openapi_aggregator_api_seconds_since_last_sync Seconds since the spec of an API was last collected successfully.
+# TYPE openapi_aggregator_api_seconds_since_last_sync gauge
+openapi_aggregator_api_seconds_since_last_sync{aggregator="metrics-test",api="team-a.orders",namespace="platform"} 120
+openapi_aggregator_api_seconds_since_last_sync{aggregator="metrics-test",api="team-b.users",namespace="platform"} 60
+`))).To(Succeed())
+
+		collector.forget(aggregator)
+		Expect(testutil.CollectAndCount(collector)).To(BeZero())
+	})
+
+	It("removes every series of a deleted aggregator", func() {
+		fetchErrors := testutil.CollectAndCount(specFetchErrors)
+		writes := testutil.CollectAndCount(configMapWrites)
+		durations := testutil.CollectAndCount(specFetchDuration)
+		sizes := testutil.CollectAndCount(configMapSizeBytes)
+
+		recordDiscoveryMetrics(aggregator, status, apis)
+		specFetchErrors.WithLabelValues(aggregator.Namespace, aggregator.Name, transformFailedReason).Inc()
+		configMapWrites.WithLabelValues(aggregator.Namespace, aggregator.Name, "update").Inc()
+		specFetchDuration.WithLabelValues(aggregator.Namespace, aggregator.Name).Observe(0.1)
+		configMapSizeBytes.WithLabelValues(aggregator.Namespace, aggregator.Name).Set(2048)
+		Expect(testutil.CollectAndCount(apiSyncAge)).To(BeNumerically(">=", 2))
+
+		forgetAggregatorMetrics(aggregator)
+
+		Expect(testutil.CollectAndCompare(discoveredServices, strings.NewReader(""))).To(Succeed())
+		Expect(testutil.CollectAndCompare(specSizeBytes, strings.NewReader(""))).To(Succeed())
+		Expect(testutil.CollectAndCount(specFetchErrors)).To(Equal(fetchErrors))
+		Expect(testutil.CollectAndCount(configMapWrites)).To(Equal(writes))
+		Expect(testutil.CollectAndCount(specFetchDuration)).To(Equal(durations))
+		Expect(testutil.CollectAndCount(configMapSizeBytes)).To(Equal(sizes))
+		apiSyncAge.mu.Lock()
+		Expect(apiSyncAge.last).NotTo(HaveKey(aggregator))
+		apiSyncAge.mu.Unlock()
+	})
+})
@@ -0,0 +1,260 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/oci"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+const (
+	// ociArtifactType is the artifact type of the image index of a pushed catalog.
+	ociArtifactType = "application/vnd.openapi-aggregator.catalog.v1"
+
+	// ociSpecArtifactType is the artifact type of the manifests holding a spec or the merged document.
+	ociSpecArtifactType = "application/vnd.openapi-aggregator.spec.v1"
+
+	// ociSpecMediaType is the media type of the layers holding a spec or the merged document.
+	ociSpecMediaType = "application/vnd.oai.openapi+json"
+
+	// ociAPIsArtifactType is the artifact type of the manifest describing the collected APIs.
+	ociAPIsArtifactType = "application/vnd.openapi-aggregator.apis.v1"
+
+	// ociAPIsMediaType is the media type of the layer describing the collected APIs.
+	ociAPIsMediaType = "application/vnd.openapi-aggregator.apis.v1+json"
+
+	// ociAPIsTitle is the file name of the layer describing the collected APIs.
+	ociAPIsTitle = "apis.json"
+
+	// ociAggregatorAnnotation records the aggregator that pushed a catalog in its image index.
+	ociAggregatorAnnotation = "observability.aggregator.io/aggregator"
+
+	// defaultOCITagTemplate is the tag template used when spec.output.oci.tagTemplate is not set.
+	defaultOCITagTemplate = "{digest}"
+
+	// ociPushTimeout bounds a push, including reading the registry credentials, so that an unresponsive
+	// registry does not hold up the reconcile for long.
+	ociPushTimeout = 30 * time.Second
+)
+
+// ociAPIEntry describes a collected API in the layer describing the collected APIs.
+type ociAPIEntry struct {
+	observabilityv1alpha1.APIInfo `json:",inline"`
+
+	// SpecLayer is the title of the layer holding the API's spec, if it was collected
+	SpecLayer string `json:"specLayer,omitempty"`
+}
+
+// pushOCI pushes the collected specs of an aggregator to its OCI registry when they changed since the
+// last successful push, and records the outcome in the status. Failures do not affect the ConfigMap
+// output; they are reported in status.oci.error and retried on the next reconcile.
+func (r *OpenAPIAggregatorReconciler) pushOCI(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator,
+	collectedAPIs []collectedAPI, merged *openapi.Document, status *observabilityv1alpha1.OpenAPIAggregatorStatus) {
+	config := instance.Spec.Output.OCI
+	if config == nil {
+		status.OCI = nil
+		return
+	}
+	logger := log.FromContext(ctx)
+
+	pushCtx, cancel := context.WithTimeout(ctx, ociPushTimeout)
+	defer cancel()
+	reference, digest, err := r.pushCatalog(pushCtx, instance, collectedAPIs, merged, status.OCI)
+	if err != nil {
+		logger.Error(err, "Failed to push specs to OCI registry", "repository", config.Repository)
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "OCIPushFailed", "Failed to push specs to %s: %v", config.Repository, err)
+		failed := &observabilityv1alpha1.OCIStatus{Error: err.Error()}
+		if status.OCI != nil {
+			failed.Reference, failed.Digest, failed.PushedAt = status.OCI.Reference, status.OCI.Digest, status.OCI.PushedAt
+		}
+		status.OCI = failed
+		return
+	}
+	if reference == "" {
+		return // Unchanged since the last push
+	}
+
+	now := metav1.Now()
+	status.OCI = &observabilityv1alpha1.OCIStatus{Reference: reference, Digest: digest, PushedAt: &now}
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "OCIPushed", "Pushed specs to %s@%s", reference, digest)
+}
+
+// pushCatalog pushes the image index of the collected specs and returns its reference and digest,
+// or an empty reference if the same index was already pushed to the repository.
+func (r *OpenAPIAggregatorReconciler) pushCatalog(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator,
+	collectedAPIs []collectedAPI, merged *openapi.Document, previous *observabilityv1alpha1.OCIStatus) (string, string, error) {
+	config := instance.Spec.Output.OCI
+	registry, repository, err := oci.ParseRepository(config.Repository)
+	if err != nil {
+		return "", "", err
+	}
+	index, err := ociCatalog(instance, collectedAPIs, merged)
+	if err != nil {
+		return "", "", err
+	}
+	_, indexJSON, err := index.Index()
+	if err != nil {
+		return "", "", err
+	}
+	digest := oci.Digest(indexJSON)
+	if previous != nil && previous.Error == "" && previous.Digest == digest &&
+		strings.HasPrefix(previous.Reference, config.Repository+":") {
+		return "", "", nil
+	}
+
+	tag := strings.NewReplacer(
+		"{digest}", strings.TrimPrefix(digest, "sha256:")[:12],
+		"{timestamp}", metav1.Now().UTC().Format("20060102150405"),
+		"{generation}", strconv.FormatInt(instance.Generation, 10),
+	).Replace(getValueOrDefault(config.TagTemplate, defaultOCITagTemplate))
+	if !oci.ValidTag(tag) {
+		return "", "", fmt.Errorf("tagTemplate %q produces invalid tag %q", config.TagTemplate, tag)
+	}
+
+	credentials, err := r.registryCredentials(ctx, instance.Namespace, config.CredentialsSecret, registry)
+	if err != nil {
+		return "", "", err
+	}
+	ociClient := &oci.Client{Credentials: credentials, PlainHTTP: config.Insecure}
+	pushed, err := ociClient.PushIndex(ctx, oci.Reference{Registry: registry, Repository: repository, Tag: tag}, index)
+	if err != nil {
+		return "", "", err
+	}
+	return config.Repository + ":" + tag, pushed, nil
+}
+
+// ociCatalog builds the image index of the collected specs: one manifest per collected spec, in key
+// order, with the spec as its single layer, one for the merged document if any, and one whose layer
+// describes all collected APIs. Fields that change on every fetch are left out, so that unchanged specs
+// produce the same index.
+func ociCatalog(instance *observabilityv1alpha1.OpenAPIAggregator, collectedAPIs []collectedAPI, merged *openapi.Document) (oci.ArtifactIndex, error) {
+	apis := append([]collectedAPI(nil), collectedAPIs...)
+	sort.Slice(apis, func(i, j int) bool { return apiKey(apis[i].Info) < apiKey(apis[j].Info) })
+
+	index := oci.ArtifactIndex{
+		ArtifactType: ociArtifactType,
+		Annotations:  map[string]string{ociAggregatorAnnotation: instance.Namespace + "/" + instance.Name},
+	}
+	entries := make([]ociAPIEntry, 0, len(apis))
+	for _, api := range apis {
+		entry := ociAPIEntry{APIInfo: *api.Info.DeepCopy()}
+		entry.LastUpdated = ""
+		if api.Document != nil {
+			spec, err := api.Document.MarshalJSON()
+			if err != nil {
+				return index, fmt.Errorf("marshaling spec of %s: %w", apiKey(api.Info), err)
+			}
+			entry.SpecLayer = apiKey(api.Info) + ".json"
+			index.Artifacts = append(index.Artifacts, ociSpecArtifact(entry.SpecLayer, spec))
+		}
+		entries = append(entries, entry)
+	}
+	if merged != nil {
+		mergedJSON, err := merged.MarshalJSON()
+		if err != nil {
+			return index, fmt.Errorf("marshaling merged spec: %w", err)
+		}
+		index.Artifacts = append(index.Artifacts, ociSpecArtifact(mergedSpecKey, mergedJSON))
+	}
+
+	entriesJSON, err := json.Marshal(entries)
+	if err != nil {
+		return index, err
+	}
+	index.Artifacts = append(index.Artifacts, oci.Artifact{
+		ArtifactType: ociAPIsArtifactType,
+		Layers:       []oci.Layer{{MediaType: ociAPIsMediaType, Title: ociAPIsTitle, Content: entriesJSON}},
+		Annotations:  map[string]string{oci.AnnotationTitle: ociAPIsTitle},
+	})
+	return index, nil
+}
+
+// ociSpecArtifact returns the artifact holding a spec under the given title.
+func ociSpecArtifact(title string, spec []byte) oci.Artifact {
+	return oci.Artifact{
+		ArtifactType: ociSpecArtifactType,
+		Layers:       []oci.Layer{{MediaType: ociSpecMediaType, Title: title, Content: spec}},
+		Annotations:  map[string]string{oci.AnnotationTitle: title},
+	}
+}
+
+// registryCredentials reads the credentials for a registry from a Secret, either a docker config
+// Secret or one with "username" and "password" keys. It returns nil if no Secret is configured.
+func (r *OpenAPIAggregatorReconciler) registryCredentials(ctx context.Context, namespace, secretName, registry string) (*oci.Credentials, error) {
+	if secretName == "" {
+		return nil, nil
+	}
+	var reader client.Reader = r.Client
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, secret); err != nil {
+		return nil, fmt.Errorf("reading credentials Secret: %w", err)
+	}
+
+	dockerConfig, ok := secret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		username, password := string(secret.Data["username"]), string(secret.Data["password"])
+		if username == "" && password == "" {
+			return nil, fmt.Errorf("credentials Secret %s has neither %s nor username and password keys", secretName, corev1.DockerConfigJsonKey)
+		}
+		return &oci.Credentials{Username: username, Password: password}, nil
+	}
+
+	var config struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(dockerConfig, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s of credentials Secret %s: %w", corev1.DockerConfigJsonKey, secretName, err)
+	}
+	for _, server := range []string{registry, "https://" + registry, "http://" + registry} {
+		auth, ok := config.Auths[server]
+		if !ok {
+			continue
+		}
+		if auth.Username == "" && auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("decoding auth of %s in credentials Secret %s: %w", server, secretName, err)
+			}
+			auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		return &oci.Credentials{Username: auth.Username, Password: auth.Password}, nil
+	}
+	return nil, fmt.Errorf("credentials Secret %s has no entry for registry %s", secretName, registry)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/oci"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+var _ = Describe("OCI catalog", func() {
+	var (
+		instance *observabilityv1alpha1.OpenAPIAggregator
+		apis     []collectedAPI
+		merged   *openapi.Document
+	)
+
+	BeforeEach(func() {
+		instance = &observabilityv1alpha1.OpenAPIAggregator{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis"}}
+		orders, err := openapi.Parse([]byte(`{"openapi":"3.0.3","info":{"title":"Orders","version":"1.0.0"},"paths":{}}`))
+		Expect(err).NotTo(HaveOccurred())
+		merged, err = openapi.Parse([]byte(`{"openapi":"3.0.3","info":{"title":"All","version":"1.0.0"},"paths":{}}`))
+		Expect(err).NotTo(HaveOccurred())
+		apis = []collectedAPI{
+			{Info: observabilityv1alpha1.APIInfo{Name: "payments", Namespace: "team-a", Error: "timeout", LastUpdated: "2025-01-01T00:00:00Z"}},
+			{Info: observabilityv1alpha1.APIInfo{Name: "orders", Namespace: "team-a", LastUpdated: "2025-01-01T00:00:00Z"}, Document: orders},
+		}
+	})
+
+	It("references one manifest per spec, the merged document and the API description", func() {
+		index, err := ociCatalog(instance, apis, merged)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.ArtifactType).To(Equal(ociArtifactType))
+		Expect(index.Annotations).To(HaveKeyWithValue(ociAggregatorAnnotation, "team-a/apis"))
+
+		Expect(index.Artifacts).To(HaveLen(3))
+		Expect(index.Artifacts[0].ArtifactType).To(Equal(ociSpecArtifactType))
+		Expect(index.Artifacts[0].Layers).To(HaveLen(1))
+		Expect(index.Artifacts[0].Layers[0].Title).To(Equal("team-a.orders.json"))
+		Expect(index.Artifacts[1].Layers[0].Title).To(Equal(mergedSpecKey))
+		Expect(index.Artifacts[2].ArtifactType).To(Equal(ociAPIsArtifactType))
+
+		var entries []ociAPIEntry
+		Expect(json.Unmarshal(index.Artifacts[2].Layers[0].Content, &entries)).To(Succeed())
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].Name).To(Equal("orders"))
+		Expect(entries[0].SpecLayer).To(Equal("team-a.orders.json"))
+		Expect(entries[0].LastUpdated).To(BeEmpty())
+		Expect(entries[1].Name).To(Equal("payments"))
+		Expect(entries[1].SpecLayer).To(BeEmpty())
+
+		pushed, indexJSON, err := index.Index()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pushed.MediaType).To(Equal(oci.MediaTypeImageIndex))
+		Expect(pushed.Manifests).To(HaveLen(3))
+		Expect(pushed.Manifests[0].Annotations).To(HaveKeyWithValue(oci.AnnotationTitle, "team-a.orders.json"))
+		Expect(indexJSON).NotTo(BeEmpty())
+	})
+
+	It("produces the same index digest when only fetch times change", func() {
+		first, err := ociCatalog(instance, apis, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, firstJSON, err := first.Index()
+		Expect(err).NotTo(HaveOccurred())
+
+		apis[0].Info.LastUpdated = "2025-01-02T00:00:00Z"
+		apis[1].Info.LastUpdated = "2025-01-02T00:00:00Z"
+		second, err := ociCatalog(instance, []collectedAPI{apis[1], apis[0]}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, secondJSON, err := second.Index()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(oci.Digest(secondJSON)).To(Equal(oci.Digest(firstJSON)))
+	})
+})
@@ -17,37 +17,101 @@ limitations under the License.
 package controller
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/catalog"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+const (
+	// mergedSpecKey is the ConfigMap key of the merged document. API keys have exactly one dot,
+	// since neither namespace nor Service names may contain one, so it cannot collide with them.
+	mergedSpecKey = "merged.openapi.json"
+
+	defaultMergedTitle      = "Aggregated API"
+	defaultMergedVersion    = "1.0.0"
+	defaultMergedPathPrefix = "/{namespace}/{name}"
+)
+
+// Condition types reported on the status of an OpenAPIAggregator.
+const (
+	// ReadyCondition is True when the last reconciliation discovered services and synced the ConfigMap.
+	ReadyCondition = "Ready"
+	// DiscoveringCondition is True while a new generation of the aggregator is being discovered.
+	DiscoveringCondition = "Discovering"
+	// ConfigMapSyncedCondition is True when the output ConfigMap matches the collected APIs.
+	ConfigMapSyncedCondition = "ConfigMapSynced"
+	// DegradedCondition is True when some namespaces could not be listed or some specs could not be collected.
+	// On a SwaggerServer, it is True when its spec cannot be applied.
+	DegradedCondition = "Degraded"
+	// BreakingChangesCondition is True when the latest spec revision of some API has breaking changes.
+	BreakingChangesCondition = "BreakingChanges"
 )
 
 // OpenAPIAggregatorReconciler reconciles a OpenAPIAggregator object
 type OpenAPIAggregatorReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Fetcher downloads and validates the OpenAPI documents of discovered services.
+	// A Fetcher with default options is used if nil.
+	Fetcher *openapi.Fetcher
+
+	// Recorder records events on aggregators and the Services they collect.
+	// The manager's recorder is used if nil.
+	Recorder record.EventRecorder
+
+	// APIReader reads objects that are not cached, such as registry credential Secrets.
+	// The client is used if nil.
+	APIReader client.Reader
+
+	// Catalog, if set, is kept up to date with the APIs collected by each aggregator, so they can be
+	// served over HTTP in addition to the output ConfigMap.
+	Catalog *catalog.Catalog
+
+	// specCache holds the last fetched spec of each API per aggregator.
+	specCache specCache
+
+	// specDigests holds the digest of each API's last collected spec per aggregator, to detect spec changes.
+	specDigests   map[types.NamespacedName]map[string]string
+	specDigestsMu sync.Mutex
 }
 
 //+kubebuilder:rbac:groups=observability.aggregator.io,resources=openapiaggregators,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=observability.aggregator.io,resources=openapiaggregators/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=observability.aggregator.io,resources=openapiaggregators/finalizers,verbs=update
-//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get
 
 // Reconcile handles the reconciliation loop for OpenAPIAggregator resources
 func (r *OpenAPIAggregatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -56,135 +120,788 @@ func (r *OpenAPIAggregatorReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	instance := &observabilityv1alpha1.OpenAPIAggregator{}
 	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
 		if errors.IsNotFound(err) {
+			r.forgetSpecDigests(req.NamespacedName)
+			forgetAggregatorMetrics(req.NamespacedName)
+			r.specCache.forget(req.NamespacedName)
+			r.unpublishCatalog(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
-	services, err := r.listServices(ctx, instance, req.Namespace)
+	if instance.Status.ObservedGeneration != instance.Generation {
+		status := instance.Status.DeepCopy()
+		setCondition(status, instance.Generation, DiscoveringCondition, metav1.ConditionTrue, "DiscoveryInProgress",
+			"Discovering services for a new generation of the aggregator")
+		if err := r.updateStatus(ctx, req.NamespacedName, *status); err != nil {
+			logger.Error(err, "Failed to update OpenAPIAggregator status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.adoptLegacyOutput(ctx, instance); err != nil {
+		logger.Error(err, "Failed to look up the legacy output ConfigMap")
+		return ctrl.Result{}, err
+	}
+
+	services, namespaceErrors, err := r.listServices(ctx, instance, req.Namespace)
 	if err != nil {
 		logger.Error(err, "Failed to list services")
+		status := instance.Status.DeepCopy()
+		status.ObservedGeneration = instance.Generation
+		status.NamespaceErrors = namespaceErrors
+		setListFailedConditions(status, instance.Generation, err)
+		if statusErr := r.updateStatus(ctx, req.NamespacedName, *status); statusErr != nil {
+			logger.Error(statusErr, "Failed to update OpenAPIAggregator status")
+		}
 		return ctrl.Result{}, err
 	}
 
-	collectedAPIs := r.collectAPIs(ctx, services, instance)
+	collectedAPIs := r.collectAPIs(ctx, services, instance, namespaceErrors)
+	r.recordRevisions(ctx, instance, collectedAPIs)
+	r.recordAPIEvents(instance, services, collectedAPIs)
+	merged, mergeConflicts := mergeAPIs(instance, collectedAPIs)
 
-	if err := r.updateStatus(ctx, req.NamespacedName, collectedAPIs); err != nil {
-		logger.Error(err, "Failed to update OpenAPIAggregator status")
-		return ctrl.Result{}, err
+	// The output is written before the status is built, as APIs whose spec cannot be stored are reported as failed.
+	output, syncErr := r.createOrUpdateConfigMap(ctx, req.Namespace, instance, collectedAPIs, merged)
+	if syncErr != nil {
+		logger.Error(syncErr, "Failed to create or update ConfigMap")
 	}
+	status := aggregatorStatus(instance, collectedAPIs, namespaceErrors, mergeConflicts)
+	if syncErr == nil {
+		status.Output = output
+	}
+	recordDiscoveryMetrics(req.NamespacedName, status, collectedAPIs)
+	r.publishCatalog(ctx, req.NamespacedName, collectedAPIs, merged)
+	r.pushOCI(ctx, instance, collectedAPIs, merged, &status)
+	setSyncConditions(&status, instance.Generation, syncErr)
 
-	if err := r.createOrUpdateConfigMap(ctx, req.Namespace, instance, collectedAPIs); err != nil {
-		logger.Error(err, "Failed to create or update ConfigMap")
+	if err := r.updateStatus(ctx, req.NamespacedName, status); err != nil {
+		logger.Error(err, "Failed to update OpenAPIAggregator status")
 		return ctrl.Result{}, err
 	}
+	if syncErr != nil {
+		return ctrl.Result{}, syncErr
+	}
 
 	logger.V(1).Info("Reconciliation completed", "collectedAPIs", len(collectedAPIs))
-	return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	// Discovery changes arrive as watch events; requeue only to fetch specs that are due again.
+	return ctrl.Result{RequeueAfter: r.specCache.requeueAfter(req.NamespacedName, time.Now(), resyncInterval(instance))}, nil
 }
 
-func (r *OpenAPIAggregatorReconciler) listServices(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator, crNamespace string) (corev1.ServiceList, error) {
+func (r *OpenAPIAggregatorReconciler) listServices(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator, crNamespace string) (corev1.ServiceList, []observabilityv1alpha1.NamespaceError, error) {
 	logger := log.FromContext(ctx)
 	var services corev1.ServiceList
+
+	selector, err := serviceSelector(instance)
+	if err != nil {
+		return services, nil, fmt.Errorf("invalid labelSelector: %w", err)
+	}
 	listOptions := []client.ListOption{}
+	if selector != nil {
+		listOptions = append(listOptions, client.MatchingLabelsSelector{Selector: selector})
+	}
 
-	switch {
-	case len(instance.Spec.WatchNamespaces) == 1 && (instance.Spec.WatchNamespaces[0] == "" || instance.Spec.WatchNamespaces[0] == "*"):
+	namespaces, allNamespaces := watchedNamespaces(instance, crNamespace)
+	if allNamespaces {
 		logger.V(1).Info("Configured to watch services in all namespaces.", "trigger", instance.Spec.WatchNamespaces)
 		// No specific namespace option needed for client.List to fetch from all namespaces.
-	case len(instance.Spec.WatchNamespaces) > 0:
-		logger.Info("Watching specific namespaces is not yet fully implemented. Defaulting to OpenAPIAggregator's namespace.", "specifiedNamespaces", instance.Spec.WatchNamespaces)
-		listOptions = append(listOptions, client.InNamespace(crNamespace))
-	default:
-		logger.V(1).Info("Configured to watch services in the same namespace as the CR.", "namespace", crNamespace)
-		listOptions = append(listOptions, client.InNamespace(crNamespace))
+		err := r.List(ctx, &services, listOptions...)
+		return services, nil, err
+	}
+
+	logger.V(1).Info("Configured to watch services in specific namespaces.", "namespaces", namespaces)
+	var namespaceErrors []observabilityv1alpha1.NamespaceError
+	for _, namespace := range namespaces {
+		var namespaceServices corev1.ServiceList
+		if err := r.List(ctx, &namespaceServices, append(listOptions, client.InNamespace(namespace))...); err != nil {
+			logger.Error(err, "Failed to list services in watched namespace", "namespace", namespace)
+			namespaceErrors = append(namespaceErrors, observabilityv1alpha1.NamespaceError{
+				Namespace: namespace,
+				Error:     err.Error(),
+			})
+			continue
+		}
+		services.Items = append(services.Items, namespaceServices.Items...)
+	}
+
+	// Partial failures are reported in status; only give up when nothing could be listed.
+	if len(namespaceErrors) == len(namespaces) {
+		return services, namespaceErrors, fmt.Errorf("failed to list services in all %d watched namespaces", len(namespaces))
+	}
+	return services, namespaceErrors, nil
+}
+
+// watchedNamespaces resolves spec.watchNamespaces into the namespaces services are listed from.
+// allNamespaces is true when the aggregator watches the whole cluster.
+func watchedNamespaces(instance *observabilityv1alpha1.OpenAPIAggregator, crNamespace string) (namespaces []string, allNamespaces bool) {
+	if len(instance.Spec.WatchNamespaces) == 0 {
+		return []string{crNamespace}, false
+	}
+
+	seen := make(map[string]bool, len(instance.Spec.WatchNamespaces))
+	for _, namespace := range instance.Spec.WatchNamespaces {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || namespace == "*" {
+			return nil, true
+		}
+		if !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces, false
+}
+
+// watchesNamespace reports whether services in the given namespace are visible to the aggregator.
+func watchesNamespace(instance *observabilityv1alpha1.OpenAPIAggregator, namespace string) bool {
+	namespaces, allNamespaces := watchedNamespaces(instance, instance.Namespace)
+	if allNamespaces {
+		return true
+	}
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// labelSelector returns spec.labelSelector and spec.labelSelectorExpressions as a label selector,
+// or nil when neither is set.
+func labelSelector(instance *observabilityv1alpha1.OpenAPIAggregator) *metav1.LabelSelector {
+	if len(instance.Spec.LabelSelector) == 0 && len(instance.Spec.LabelSelectorExpressions) == 0 {
+		return nil
+	}
+	return &metav1.LabelSelector{
+		MatchLabels:      instance.Spec.LabelSelector,
+		MatchExpressions: instance.Spec.LabelSelectorExpressions,
 	}
+}
 
-	if err := r.List(ctx, &services, listOptions...); err != nil {
-		return services, err
+// serviceSelector converts the aggregator's label selector into a labels.Selector.
+// It returns nil when no label selector is configured.
+func serviceSelector(instance *observabilityv1alpha1.OpenAPIAggregator) (labels.Selector, error) {
+	selector := labelSelector(instance)
+	if selector == nil {
+		return nil, nil
 	}
-	return services, nil
+	return metav1.LabelSelectorAsSelector(selector)
 }
 
-func (r *OpenAPIAggregatorReconciler) collectAPIs(ctx context.Context, services corev1.ServiceList, instance *observabilityv1alpha1.OpenAPIAggregator) []observabilityv1alpha1.APIInfo {
+// selectsService reports whether the aggregator collects the given Service.
+// Services in watched namespaces are selected by the swagger annotation, or by the label selector if one is set.
+func selectsService(instance *observabilityv1alpha1.OpenAPIAggregator, svc *corev1.Service) bool {
+	if !watchesNamespace(instance, svc.Namespace) {
+		return false
+	}
+
+	selector, err := serviceSelector(instance)
+	if err != nil {
+		return false
+	}
+	if selector != nil && !selector.Matches(labels.Set(svc.Labels)) {
+		return false
+	}
+
+	if value, ok := svc.Annotations[instance.Spec.SwaggerAnnotation]; ok {
+		return value == "true"
+	}
+	return selector != nil
+}
+
+// collectedAPI is an API discovered by the aggregator together with the document fetched for it.
+type collectedAPI struct {
+	Info observabilityv1alpha1.APIInfo
+
+	// Service is the Service the API was discovered from
+	Service *corev1.Service
+
+	// SpecSize is the size in bytes of the spec as served; zero if it could not be fetched
+	SpecSize int
+
+	// Document is the fetched spec; nil if it could not be fetched or parsed
+	Document *openapi.Document
+}
+
+// apiInfos returns the status entries of the collected APIs.
+func apiInfos(apis []collectedAPI) []observabilityv1alpha1.APIInfo {
+	infos := make([]observabilityv1alpha1.APIInfo, 0, len(apis))
+	for _, api := range apis {
+		infos = append(infos, api.Info)
+	}
+	return infos
+}
+
+func (r *OpenAPIAggregatorReconciler) collectAPIs(ctx context.Context, services corev1.ServiceList, instance *observabilityv1alpha1.OpenAPIAggregator,
+	namespaceErrors []observabilityv1alpha1.NamespaceError) []collectedAPI {
 	logger := log.FromContext(ctx)
-	var collectedAPIs []observabilityv1alpha1.APIInfo
+	var collectedAPIs []collectedAPI
 	for _, service := range services.Items {
 		if apiInfo := r.processService(ctx, service, instance); apiInfo != nil {
 			logger.V(1).Info("Collected API info", "service", service.Name, "url", apiInfo.URL)
-			collectedAPIs = append(collectedAPIs, *apiInfo)
+			collectedAPIs = append(collectedAPIs, collectedAPI{Info: *apiInfo, Service: service.DeepCopy()})
 		}
 	}
+	collectedAPIs = append(collectedAPIs, retainedAPIs(instance, namespaceErrors)...)
+	r.fetchSpecs(ctx, instance, collectedAPIs)
 	return collectedAPIs
 }
 
-func (r *OpenAPIAggregatorReconciler) updateStatus(ctx context.Context, namespacedName types.NamespacedName, collectedAPIs []observabilityv1alpha1.APIInfo) error {
+// retainedAPIs returns the previously collected APIs of the namespaces whose services could not be listed,
+// so that a transient listing error does not drop them from the outputs. Their specs are still fetched.
+func retainedAPIs(instance *observabilityv1alpha1.OpenAPIAggregator, namespaceErrors []observabilityv1alpha1.NamespaceError) []collectedAPI {
+	if len(namespaceErrors) == 0 {
+		return nil
+	}
+	failed := make(map[string]bool, len(namespaceErrors))
+	for _, namespaceError := range namespaceErrors {
+		failed[namespaceError.Namespace] = true
+	}
+
+	var apis []collectedAPI
+	for _, previous := range instance.Status.CollectedAPIs {
+		if !failed[previous.Namespace] {
+			continue
+		}
+		info := observabilityv1alpha1.APIInfo{
+			Name:           previous.Name,
+			URL:            previous.URL,
+			ResourceType:   previous.ResourceType,
+			ResourceName:   previous.ResourceName,
+			Namespace:      previous.Namespace,
+			Path:           previous.Path,
+			Port:           previous.Port,
+			Annotations:    previous.Annotations,
+			AllowedMethods: previous.AllowedMethods,
+		}
+		if previous.URL == "" {
+			// The Service's annotations did not yield a URL; keep reporting why.
+			info.Error = previous.Error
+		}
+		apis = append(apis, collectedAPI{Info: info})
+	}
+	return apis
+}
+
+// fetchSpecs downloads and validates the spec of every collected API that is due to be fetched,
+// reusing the last fetch of the others, and records the outcome in its APIInfo.
+func (r *OpenAPIAggregatorReconciler) fetchSpecs(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator, apis []collectedAPI) {
+	logger := log.FromContext(ctx)
+	aggregator := types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}
+	now := time.Now()
+
+	states := make([]fetchState, len(apis))
+	keys := make(map[string]bool, len(apis))
+	var requests []openapi.Request
+	var pending []int
+	for i := range apis {
+		if apis[i].Info.Error != "" {
+			// The Service's annotations do not yield a URL to fetch the spec from.
+			states[i] = fetchState{Result: openapi.Result{Err: goerrors.New(apis[i].Info.Error), Reason: openapi.ReasonInvalidURL}}
+			continue
+		}
+		key := apiKey(apis[i].Info)
+		keys[key] = true
+		if state, ok := r.specCache.get(aggregator, key, apis[i].Info.URL, now); ok {
+			states[i] = state
+			continue
+		}
+		requests = append(requests, specRequest(instance, apis[i]))
+		pending = append(pending, i)
+	}
+
+	fetchDuration := specFetchDuration.WithLabelValues(instance.Namespace, instance.Name)
+	for j, result := range r.Fetcher.FetchAll(ctx, requests) {
+		i := pending[j]
+		fetchDuration.Observe(result.Duration.Seconds())
+		if result.Err != nil {
+			logger.V(1).Info("API spec fetch failed", "name", apis[i].Info.Name, "namespace", apis[i].Info.Namespace, "url", apis[i].Info.URL, "error", result.Err)
+			specFetchErrors.WithLabelValues(instance.Namespace, instance.Name, result.Reason).Inc()
+		}
+		states[i] = r.specCache.put(aggregator, apiKey(apis[i].Info), apis[i].Info.URL, result, now, resyncInterval(instance))
+	}
+	r.specCache.retain(aggregator, keys)
+
+	// LastUpdated only moves forward when a spec is fetched successfully.
+	lastUpdated := make(map[string]string, len(instance.Status.CollectedAPIs))
+	for _, api := range instance.Status.CollectedAPIs {
+		lastUpdated[apiKey(api)] = api.LastUpdated
+	}
+
+	for i, state := range states {
+		api := &apis[i].Info
+		api.HTTPStatus = int32(state.Result.StatusCode)
+		if state.Result.Err != nil {
+			api.Error = state.Result.Err.Error()
+			api.LastUpdated = lastUpdated[apiKey(*api)]
+			continue
+		}
+		api.Error = ""
+		api.SpecVersion = state.Result.Document.SpecVersion
+		api.Title = state.Result.Document.Title
+		api.Version = state.Result.Document.Version
+		api.LastUpdated = state.FetchedAt.Format(time.RFC3339)
+		apis[i].Document = state.Result.Document
+		apis[i].SpecSize = len(state.Result.Body)
+		transformSpec(instance, &apis[i])
+		if api.Error != "" {
+			specFetchErrors.WithLabelValues(instance.Namespace, instance.Name, transformFailedReason).Inc()
+			api.LastUpdated = lastUpdated[apiKey(*api)]
+		}
+	}
+}
+
+// transformSpec applies the aggregator's rewrites to a successfully fetched spec.
+func transformSpec(instance *observabilityv1alpha1.OpenAPIAggregator, api *collectedAPI) {
+	if instance.Spec.ConvertSwagger2 && api.Document.IsSwagger2() {
+		converted, warnings, err := openapi.ConvertSwagger2(api.Document)
+		if err != nil {
+			api.Info.Error = fmt.Sprintf("failed to convert Swagger 2.0 spec: %v", err)
+			return
+		}
+		api.Info.ConvertedFrom = api.Document.SpecVersion
+		api.Info.ConversionWarnings = warnings
+		api.Info.SpecVersion = converted.SpecVersion
+		api.Document = converted
+	}
+
+	// Enforce the allowed methods on the spec itself, so the stored and merged output only exposes them.
+	if len(api.Info.AllowedMethods) > 0 {
+		api.Info.PrunedOperations = int32(openapi.PruneOperations(api.Document, api.Info.AllowedMethods))
+	}
+
+	if instance.Spec.ServerRewrite != nil {
+		serverURL, err := serverBaseURL(instance.Spec.ServerRewrite, api.Info)
+		if err == nil {
+			err = openapi.RewriteServers(api.Document, serverURL)
+		}
+		if err != nil {
+			api.Info.Error = fmt.Sprintf("failed to rewrite servers: %v", err)
+			return
+		}
+		api.Info.ServerURL = serverURL
+	}
+}
+
+// serverBaseURL returns the base URL to write into the servers of an API's spec.
+func serverBaseURL(config *observabilityv1alpha1.ServerRewriteSpec, api observabilityv1alpha1.APIInfo) (string, error) {
+	if config.URLTemplate != "" {
+		return strings.NewReplacer("{namespace}", api.Namespace, "{name}", api.Name, "{port}", api.Port).Replace(config.URLTemplate), nil
+	}
+
+	// Default to the in-cluster address the spec itself was fetched from.
+	specURL, err := url.Parse(api.URL)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s://%s", specURL.Scheme, specURL.Host), nil
+}
+
+// apiKey returns the key identifying an API within an aggregator, which is also its ConfigMap key.
+func apiKey(api observabilityv1alpha1.APIInfo) string {
+	return fmt.Sprintf("%s.%s", api.Namespace, api.Name)
+}
+
+// mergeAPIs combines the collected specs into one OpenAPI 3 document when spec.output.merged is set.
+func mergeAPIs(instance *observabilityv1alpha1.OpenAPIAggregator, apis []collectedAPI) (*openapi.Document, []observabilityv1alpha1.MergeConflict) {
+	config := instance.Spec.Output.Merged
+	if config == nil {
+		return nil, nil
+	}
+
+	pathPrefix := getValueOrDefault(config.PathPrefix, defaultMergedPathPrefix)
+	sources := make([]openapi.MergeSource, 0, len(apis))
+	for _, api := range apis {
+		if api.Document == nil {
+			continue
+		}
+		sources = append(sources, openapi.MergeSource{
+			Key:        apiKey(api.Info),
+			PathPrefix: strings.NewReplacer("{namespace}", api.Info.Namespace, "{name}", api.Info.Name).Replace(pathPrefix),
+			Document:   api.Document,
+		})
+	}
+
+	merged, conflicts := openapi.Merge(sources, openapi.MergeOptions{
+		Title:   getValueOrDefault(config.Title, defaultMergedTitle),
+		Version: getValueOrDefault(config.Version, defaultMergedVersion),
+	})
+	var mergeConflicts []observabilityv1alpha1.MergeConflict
+	for _, conflict := range conflicts {
+		mergeConflicts = append(mergeConflicts, observabilityv1alpha1.MergeConflict{API: conflict.Source, Message: conflict.Message})
+	}
+	return merged, mergeConflicts
+}
+
+// aggregatorStatus builds the status of an aggregator from the outcome of discovery and collection.
+// Conditions and the last sync time are carried over from the current status.
+func aggregatorStatus(instance *observabilityv1alpha1.OpenAPIAggregator, collectedAPIs []collectedAPI,
+	namespaceErrors []observabilityv1alpha1.NamespaceError, mergeConflicts []observabilityv1alpha1.MergeConflict) observabilityv1alpha1.OpenAPIAggregatorStatus {
+	current := instance.Status.DeepCopy()
+	status := observabilityv1alpha1.OpenAPIAggregatorStatus{
+		ObservedGeneration: instance.Generation,
+		Conditions:         current.Conditions,
+		LastSyncTime:       current.LastSyncTime,
+		Output:             current.Output,
+		OCI:                current.OCI,
+		CollectedAPIs:      apiInfos(collectedAPIs),
+		NamespaceErrors:    namespaceErrors,
+		MergeConflicts:     mergeConflicts,
+	}
+
+	var failed []string
+	for _, api := range status.CollectedAPIs {
+		if api.Error != "" {
+			failed = append(failed, apiKey(api))
+		}
+	}
+	status.DiscoveredAPIs = int32(len(status.CollectedAPIs))
+	status.FailedAPIs = int32(len(failed))
+	status.HealthyAPIs = status.DiscoveredAPIs - status.FailedAPIs
+
+	setCondition(&status, instance.Generation, DiscoveringCondition, metav1.ConditionFalse, "DiscoveryComplete",
+		fmt.Sprintf("Discovered %d APIs", status.DiscoveredAPIs))
+	switch {
+	case len(namespaceErrors) > 0:
+		setCondition(&status, instance.Generation, DegradedCondition, metav1.ConditionTrue, "NamespaceListFailed",
+			fmt.Sprintf("Services could not be listed in %d watched namespaces", len(namespaceErrors)))
+	case len(failed) > 0:
+		setCondition(&status, instance.Generation, DegradedCondition, metav1.ConditionTrue, "SpecCollectionFailed",
+			fmt.Sprintf("%d of %d APIs failed: %s", len(failed), status.DiscoveredAPIs, strings.Join(failed, ", ")))
+	default:
+		setCondition(&status, instance.Generation, DegradedCondition, metav1.ConditionFalse, "AllAPIsHealthy",
+			fmt.Sprintf("All %d APIs are healthy", status.DiscoveredAPIs))
+	}
+	setBreakingChangesCondition(&status, instance)
+	return status
+}
+
+// setListFailedConditions records on the status that no watched namespace could be listed.
+func setListFailedConditions(status *observabilityv1alpha1.OpenAPIAggregatorStatus, generation int64, err error) {
+	setCondition(status, generation, DiscoveringCondition, metav1.ConditionFalse, "ListServicesFailed", err.Error())
+	setCondition(status, generation, DegradedCondition, metav1.ConditionTrue, "ListServicesFailed", err.Error())
+	setCondition(status, generation, ReadyCondition, metav1.ConditionFalse, "ListServicesFailed", err.Error())
+}
+
+// setSyncConditions records the outcome of syncing the output ConfigMap on the status.
+func setSyncConditions(status *observabilityv1alpha1.OpenAPIAggregatorStatus, generation int64, syncErr error) {
+	if syncErr != nil {
+		reason := "ConfigMapSyncFailed"
+		if goerrors.Is(syncErr, errConfigMapConflict) {
+			reason = "ConfigMapConflict"
+		}
+		message := fmt.Sprintf("Failed to sync ConfigMap: %v", syncErr)
+		setCondition(status, generation, ConfigMapSyncedCondition, metav1.ConditionFalse, reason, message)
+		setCondition(status, generation, ReadyCondition, metav1.ConditionFalse, reason, message)
+		return
+	}
+
+	now := metav1.Now()
+	status.LastSyncTime = &now
+	setCondition(status, generation, ConfigMapSyncedCondition, metav1.ConditionTrue, "ConfigMapSynced",
+		"ConfigMap is up to date with the collected APIs")
+	setCondition(status, generation, ReadyCondition, metav1.ConditionTrue, "Reconciled",
+		fmt.Sprintf("%d of %d APIs healthy", status.HealthyAPIs, status.DiscoveredAPIs))
+}
+
+// setCondition sets a condition on the status, keeping its transition time if the status did not change.
+func setCondition(status *observabilityv1alpha1.OpenAPIAggregatorStatus, generation int64, conditionType string,
+	conditionStatus metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// updateStatus replaces the status of the aggregator with the given one.
+func (r *OpenAPIAggregatorReconciler) updateStatus(ctx context.Context, namespacedName types.NamespacedName, status observabilityv1alpha1.OpenAPIAggregatorStatus) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		latest := &observabilityv1alpha1.OpenAPIAggregator{}
 		if err := r.Get(ctx, namespacedName, latest); err != nil {
 			return err
 		}
-		latest.Status.CollectedAPIs = collectedAPIs
+		latest.Status = status
 		return r.Status().Update(ctx, latest)
 	})
 }
 
-func (r *OpenAPIAggregatorReconciler) createOrUpdateConfigMap(ctx context.Context, namespace string, instance *observabilityv1alpha1.OpenAPIAggregator, collectedAPIs []observabilityv1alpha1.APIInfo) error {
+// configMapEntry is the JSON document stored under each API's key in the output ConfigMap
+type configMapEntry struct {
+	observabilityv1alpha1.APIInfo `json:",inline"`
+
+	// Spec is the content of the fetched document, stored when spec.output.storeSpecs is enabled
+	Spec json.RawMessage `json:"spec,omitempty"`
+
+	// SpecEncoding is "gzip" when the document is stored compressed in binaryData under SpecKey instead of in Spec
+	SpecEncoding string `json:"specEncoding,omitempty"`
+
+	// SpecKey is the binaryData key of the compressed document
+	SpecKey string `json:"specKey,omitempty"`
+
+	// SpecChecksum is the SHA-256 checksum of the uncompressed document, as "sha256:<hex>"
+	SpecChecksum string `json:"specChecksum,omitempty"`
+}
+
+// createOrUpdateConfigMap writes the entries of the collected APIs and the merged document to the output
+// ConfigMap, sharding them over several ConfigMaps when they do not fit into one.
+func (r *OpenAPIAggregatorReconciler) createOrUpdateConfigMap(ctx context.Context, namespace string, instance *observabilityv1alpha1.OpenAPIAggregator, collectedAPIs []collectedAPI, merged *openapi.Document) (observabilityv1alpha1.OutputStatus, error) {
 	logger := log.FromContext(ctx)
-	cm := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "openapi-specs",
-			Namespace: namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: instance.APIVersion,
-					Kind:       instance.Kind,
-					Name:       instance.Name,
-					UID:        instance.UID,
-					Controller: &[]bool{true}[0],
-				},
-			},
-		},
-		Data: map[string]string{},
+	configMapName := outputConfigMapName(instance)
+
+	previous, placement, err := r.readOutput(ctx, namespace, configMapName)
+	if err != nil {
+		return observabilityv1alpha1.OutputStatus{ConfigMapName: configMapName}, err
 	}
 
-	for _, api := range collectedAPIs {
-		apiJSON, err := json.Marshal(api)
+	entries := map[string]outputEntry{}
+	for i := range collectedAPIs {
+		api := &collectedAPIs[i]
+		entry, err := apiOutputEntry(instance, api, previous[apiKey(api.Info)])
 		if err != nil {
-			logger.Error(err, "Failed to marshal API info", "api", api.Name)
+			logger.Error(err, "Failed to marshal API info", "api", api.Info.Name)
 			continue
 		}
-		cm.Data[fmt.Sprintf("%s.%s", api.Namespace, api.Name)] = string(apiJSON)
+		entries[apiKey(api.Info)] = entry
+	}
+
+	if merged != nil {
+		entry, err := mergedOutputEntry(instance, merged)
+		switch {
+		case err != nil:
+			logger.Error(err, "Failed to marshal merged API spec")
+		case entry.size() > maxConfigMapDataBytes:
+			// Storing it would fail on every reconcile; the other entries are still written.
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "MergedSpecTooLarge",
+				"Merged spec takes %d bytes, more than the %d bytes an output ConfigMap can hold; it is not stored", entry.size(), maxConfigMapDataBytes)
+		default:
+			entries[mergedSpecKey] = entry
+		}
 	}
 
-	foundCm := &corev1.ConfigMap{}
-	err := r.Client.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, foundCm)
+	configMapSizeBytes.WithLabelValues(instance.Namespace, instance.Name).Set(float64(configMapDataSize(entries)))
+	output, err := r.writeOutput(ctx, instance, configMapName, entries, placement)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			logger.Info("ConfigMap not found, creating new one", "ConfigMap.Name", cm.Name, "ConfigMap.Namespace", cm.Namespace)
-			return r.Client.Create(ctx, cm)
+		return output, err
+	}
+
+	// Output written under a previous name is no longer read by anyone.
+	if previousName := instance.Status.Output.ConfigMapName; previousName != "" && previousName != configMapName {
+		if err := r.deleteOutput(ctx, instance, previousName); err != nil {
+			logger.Error(err, "Failed to delete previous output ConfigMap", "ConfigMap.Name", previousName)
 		}
-		return err
 	}
-	// Only update if data has changed
-	if !r.isConfigMapDataEqual(foundCm.Data, cm.Data) {
-		foundCm.Data = cm.Data // Update data
-		return r.Client.Update(ctx, foundCm)
+	return output, nil
+}
+
+// apiOutputEntry returns the ConfigMap keys stored for an API. A spec too large for a ConfigMap is left
+// out and reported in the API's error instead.
+func apiOutputEntry(instance *observabilityv1alpha1.OpenAPIAggregator, api *collectedAPI, previous outputEntry) (outputEntry, error) {
+	output, err := buildAPIOutputEntry(instance, *api, previous, instance.Spec.Output.StoreSpecs)
+	if err != nil || output.size() <= maxConfigMapDataBytes {
+		return output, err
+	}
+	// Writing an entry larger than a ConfigMap can hold would fail on every reconcile.
+	api.Info.Error = fmt.Sprintf("stored spec takes %d bytes, more than the %d bytes an output ConfigMap can hold; it is not stored",
+		output.size(), maxConfigMapDataBytes)
+	return buildAPIOutputEntry(instance, *api, previous, false)
+}
+
+// buildAPIOutputEntry returns the ConfigMap keys stored for an API, with its spec if storeSpec is set.
+// With spec.output.compressSpecs the document goes gzip-compressed into binaryData; the compressed bytes
+// of the previous entry are reused when the document did not change, so that the ConfigMap is not rewritten.
+func buildAPIOutputEntry(instance *observabilityv1alpha1.OpenAPIAggregator, api collectedAPI, previous outputEntry, storeSpec bool) (outputEntry, error) {
+	key := apiKey(api.Info)
+	entry := configMapEntry{APIInfo: api.Info}
+	output := outputEntry{Data: map[string]string{}}
+
+	if storeSpec {
+		previousEntry := decodeConfigMapEntry(previous.Data[key])
+		spec, err := storedSpec(api, previousEntry, previous)
+		if err != nil {
+			return output, err
+		}
+		if spec != nil {
+			entry.SpecChecksum = specChecksum(spec)
+			if instance.Spec.Output.CompressSpecs {
+				entry.SpecEncoding = gzipEncoding
+				entry.SpecKey = key + compressedKeySuffix
+				compressed := previous.BinaryData[entry.SpecKey]
+				if compressed == nil || previousEntry.SpecChecksum != entry.SpecChecksum {
+					if compressed, err = gzipBytes(spec); err != nil {
+						return output, err
+					}
+				}
+				output.BinaryData = map[string][]byte{entry.SpecKey: compressed}
+			} else {
+				entry.Spec = spec
+			}
+		}
+	}
+
+	apiJSON, err := json.Marshal(entry)
+	if err != nil {
+		return output, err
+	}
+	output.Data[key] = string(apiJSON)
+	return output, nil
+}
+
+// mergedOutputEntry returns the ConfigMap keys stored for the merged document, compressed into
+// binaryData under mergedSpecKey with a ".gz" suffix when spec.output.compressSpecs is enabled.
+func mergedOutputEntry(instance *observabilityv1alpha1.OpenAPIAggregator, merged *openapi.Document) (outputEntry, error) {
+	mergedJSON, err := merged.MarshalJSON()
+	if err != nil {
+		return outputEntry{}, err
+	}
+	if !instance.Spec.Output.CompressSpecs {
+		return outputEntry{Data: map[string]string{mergedSpecKey: string(mergedJSON)}}, nil
+	}
+	compressed, err := gzipBytes(mergedJSON)
+	if err != nil {
+		return outputEntry{}, err
+	}
+	return outputEntry{BinaryData: map[string][]byte{mergedSpecKey + compressedKeySuffix: compressed}}, nil
+}
+
+// publishCatalog publishes the collected APIs and the merged document of an aggregator to the catalog, if any.
+func (r *OpenAPIAggregatorReconciler) publishCatalog(ctx context.Context, aggregator types.NamespacedName, collectedAPIs []collectedAPI, merged *openapi.Document) {
+	if r.Catalog == nil {
+		return
+	}
+	logger := log.FromContext(ctx)
+
+	entries := make([]catalog.Published, 0, len(collectedAPIs))
+	for _, api := range collectedAPIs {
+		entry := catalog.Published{Info: api.Info}
+		if api.Document != nil {
+			spec, err := api.Document.MarshalJSON()
+			if err != nil {
+				logger.Error(err, "Failed to marshal API spec for the catalog", "api", api.Info.Name)
+			}
+			entry.Spec = spec
+		}
+		entries = append(entries, entry)
+	}
+
+	var mergedJSON []byte
+	if merged != nil {
+		var err error
+		if mergedJSON, err = merged.MarshalJSON(); err != nil {
+			logger.Error(err, "Failed to marshal merged API spec for the catalog")
+		}
+	}
+	r.Catalog.Set(aggregator, entries, mergedJSON)
+}
+
+// unpublishCatalog removes a deleted aggregator from the catalog, if any.
+func (r *OpenAPIAggregatorReconciler) unpublishCatalog(aggregator types.NamespacedName) {
+	if r.Catalog != nil {
+		r.Catalog.Delete(aggregator)
+	}
+}
+
+// outputConfigMapName returns the name of the aggregator's output ConfigMap. Without spec.output.configMapName,
+// an aggregator whose output is in the legacy ConfigMap keeps it there, see adoptLegacyOutput.
+func outputConfigMapName(instance *observabilityv1alpha1.OpenAPIAggregator) string {
+	if instance.Spec.Output.ConfigMapName == "" && instance.Status.Output.ConfigMapName == legacyConfigMapName {
+		return legacyConfigMapName
+	}
+	return getValueOrDefault(instance.Spec.Output.ConfigMapName, instance.Name+"-specs")
+}
+
+// adoptLegacyOutput records in the status of an aggregator that has not reported its output yet that the
+// output is the legacy ConfigMap, if the aggregator controls one, so that SwaggerServers reading it keep
+// working after an upgrade. Setting spec.output.configMapName moves the output and deletes the legacy ConfigMap.
+func (r *OpenAPIAggregatorReconciler) adoptLegacyOutput(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator) error {
+	if instance.Spec.Output.ConfigMapName != "" || instance.Status.Output.ConfigMapName != "" {
+		return nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: legacyConfigMapName}, cm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if metav1.IsControlledBy(cm, instance) {
+		instance.Status.Output.ConfigMapName = legacyConfigMapName
+	}
+	return nil
+}
+
+// configMapDataSize returns the number of bytes taken by the keys and values of output entries.
+func configMapDataSize(entries map[string]outputEntry) int {
+	size := 0
+	for _, entry := range entries {
+		size += entry.size()
+	}
+	return size
+}
+
+// decodeConfigMapEntry decodes a stored API entry, returning an empty entry if it cannot be decoded.
+func decodeConfigMapEntry(value string) configMapEntry {
+	var entry configMapEntry
+	if value != "" {
+		_ = json.Unmarshal([]byte(value), &entry)
+	}
+	return entry
+}
+
+// storedSpec returns the spec content to store for an API. When the spec could not be fetched,
+// the snapshot from the previous entry is kept so the UI keeps serving the last known good document.
+func storedSpec(api collectedAPI, previousEntry configMapEntry, previous outputEntry) (json.RawMessage, error) {
+	if api.Document != nil {
+		return api.Document.MarshalJSON()
+	}
+	spec := previousEntry.Spec
+	if previousEntry.SpecEncoding == gzipEncoding {
+		compressed, ok := previous.BinaryData[previousEntry.SpecKey]
+		if !ok {
+			return nil, nil
+		}
+		var err error
+		if spec, err = gunzipBytes(compressed); err != nil {
+			return nil, nil
+		}
+	}
+	return pruneSnapshot(spec, api.Info.AllowedMethods)
+}
+
+// pruneSnapshot removes the operations the current allowed methods no longer allow from a previously
+// stored spec, which was pruned with the allowed methods of its time. A snapshot that cannot be parsed
+// is dropped rather than published unpruned.
+func pruneSnapshot(spec json.RawMessage, allowedMethods []string) (json.RawMessage, error) {
+	if len(spec) == 0 || len(allowedMethods) == 0 {
+		return spec, nil
+	}
+	document, err := openapi.Parse(spec)
+	if err != nil {
+		return nil, nil
+	}
+	if openapi.PruneOperations(document, allowedMethods) == 0 {
+		return spec, nil
 	}
-	return nil // No update needed
+	return document.MarshalJSON()
 }
 
-// isConfigMapDataEqual checks if two ConfigMap data are equal.
-func (r *OpenAPIAggregatorReconciler) isConfigMapDataEqual(d1, d2 map[string]string) bool {
-	if len(d1) != len(d2) {
+// isConfigMapDataEqual checks if the data and binary data of two ConfigMaps are equal.
+func (r *OpenAPIAggregatorReconciler) isConfigMapDataEqual(cm1, cm2 *corev1.ConfigMap) bool {
+	if len(cm1.Data) != len(cm2.Data) || len(cm1.BinaryData) != len(cm2.BinaryData) {
 		return false
 	}
-	for k, v1 := range d1 {
-		v2, ok := d2[k]
+	for k, v1 := range cm1.Data {
+		v2, ok := cm2.Data[k]
 		if !ok || v1 != v2 {
 			return false
 		}
 	}
+	for k, v1 := range cm1.BinaryData {
+		v2, ok := cm2.BinaryData[k]
+		if !ok || !bytes.Equal(v1, v2) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -194,9 +911,10 @@ func (r *OpenAPIAggregatorReconciler) processService(ctx context.Context, svc co
 
 	// logger.Info("Processing service in processService", "serviceName", svc.Name, "serviceNamespace", svc.Namespace) // Reverted
 
-	// Check if the service has the required swagger annotation
+	// Check if the service has the required swagger annotation.
+	// Services listed through a label selector are opted in without it.
 	annotationValue, annotationExists := svc.Annotations[instance.Spec.SwaggerAnnotation]
-	if !annotationExists {
+	if !annotationExists && labelSelector(instance) == nil {
 		logger.V(1).Info("Skipping service - swagger annotation not found", // Reverted to V(1)
 			"service", svc.Name,
 			"namespace", svc.Namespace,
@@ -204,7 +922,7 @@ func (r *OpenAPIAggregatorReconciler) processService(ctx context.Context, svc co
 		return nil
 	}
 
-	if annotationValue != "true" {
+	if annotationExists && annotationValue != "true" {
 		logger.V(1).Info("Skipping service - swagger annotation value is not 'true'", // Reverted to V(1)
 			"service", svc.Name,
 			"namespace", svc.Namespace,
@@ -219,7 +937,7 @@ func (r *OpenAPIAggregatorReconciler) processService(ctx context.Context, svc co
 		path = instance.Spec.DefaultPath
 	}
 
-	port := svc.Annotations[instance.Spec.PortAnnotation]
+	port := strings.TrimSpace(svc.Annotations[instance.Spec.PortAnnotation])
 	if port == "" {
 		port = instance.Spec.DefaultPort
 	}
@@ -253,67 +971,215 @@ func (r *OpenAPIAggregatorReconciler) processService(ctx context.Context, svc co
 		Namespace:      svc.Namespace,
 		Path:           path,
 		Port:           port,
-		URL:            fmt.Sprintf("http://%s.%s.svc.cluster.local:%s%s", svc.Name, svc.Namespace, port, path),
-		LastUpdated:    time.Now().Format(time.RFC3339),
 		Annotations:    svc.Annotations,
 		AllowedMethods: allowedMethods,
 	}
-
-	// Enable health check to validate accessibility
-	// TODO: Uncomment this line for production use
-	// r.checkAPIHealth(ctx, apiInfo)
+	if err := setSpecURL(instance, &svc, apiInfo); err != nil {
+		// The spec is not fetched; the error is reported on the API's status entry.
+		apiInfo.Error = err.Error()
+	}
 
 	return apiInfo
 }
 
-// // checkAPIHealth verifies if the OpenAPI endpoint is accessible
-// func (r *OpenAPIAggregatorReconciler) checkAPIHealth(ctx context.Context, apiInfo *observabilityv1alpha1.APIInfo) {
-// 	logger := log.FromContext(ctx).V(1)
-
-// 	client := &http.Client{Timeout: 5 * time.Second}
-// 	resp, err := client.Get(apiInfo.URL)
-// 	if err != nil {
-// 		logger.V(1).Info("API health check failed", "name", apiInfo.Name, "error", err)
-// 		apiInfo.Error = fmt.Sprintf("Failed to access OpenAPI endpoint: %v", err)
-// 		return
-// 	}
-// 	defer func() {
-// 		if err := resp.Body.Close(); err != nil {
-// 			logger.Error(err, "Failed to close response body")
-// 		}
-// 	}()
-
-// 	if resp.StatusCode != http.StatusOK {
-// 		logger.V(1).Info("API health check failed",
-// 			"name", apiInfo.Name,
-// 			"statusCode", resp.StatusCode)
-// 		apiInfo.Error = fmt.Sprintf("OpenAPI endpoint returned non-200 status: %d", resp.StatusCode)
-// 		return
-// 	}
-
-// 	logger.V(2).Info("API health check successful", "name", apiInfo.Name)
-// 	apiInfo.Error = ""
-// }
+// setSpecURL sets the URL the spec of a Service is fetched from: the URL annotation if present and allowed,
+// otherwise the in-cluster address of the Service with the scheme from the scheme annotation and the API's
+// port and path.
+func setSpecURL(instance *observabilityv1alpha1.OpenAPIAggregator, svc *corev1.Service, api *observabilityv1alpha1.APIInfo) error {
+	if override := strings.TrimSpace(svc.Annotations[instance.Spec.URLAnnotation]); override != "" {
+		if !instance.Spec.AllowURLOverride {
+			return fmt.Errorf("%s annotation is not honored: spec.allowURLOverride is not set", instance.Spec.URLAnnotation)
+		}
+		specURL, err := url.Parse(override)
+		if err != nil || (specURL.Scheme != "http" && specURL.Scheme != "https") || specURL.Host == "" {
+			return fmt.Errorf("invalid %s annotation %q: must be an absolute http or https URL", instance.Spec.URLAnnotation, override)
+		}
+		api.URL = specURL.String()
+		api.Path = specURL.Path
+		api.Port = specURL.Port()
+		if api.Port == "" {
+			api.Port = defaultSchemePorts[specURL.Scheme]
+		}
+		return nil
+	}
+
+	scheme := strings.ToLower(strings.TrimSpace(svc.Annotations[instance.Spec.SchemeAnnotation]))
+	if scheme == "" {
+		scheme = "http"
+	}
+	if _, ok := defaultSchemePorts[scheme]; !ok {
+		return fmt.Errorf("invalid %s annotation %q: must be http or https", instance.Spec.SchemeAnnotation, scheme)
+	}
+	port, err := resolveServicePort(svc, api.Port, strings.TrimSpace(svc.Annotations[instance.Spec.PortAnnotation]) != "")
+	if err != nil {
+		return err
+	}
+	api.Port = port
+	api.URL = fmt.Sprintf("%s://%s.%s.svc.cluster.local:%s%s", scheme, svc.Name, svc.Namespace, api.Port, api.Path)
+	return nil
+}
+
+// resolveServicePort returns the number of the Service port matching port by name or number. When an
+// unannotated default port is not exposed by a Service with a single port, that port is used instead.
+// Ports of a Service that declares none, such as an ExternalName Service, are used as given if numeric.
+func resolveServicePort(svc *corev1.Service, port string, annotated bool) (string, error) {
+	if len(svc.Spec.Ports) == 0 {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return "", fmt.Errorf("port %q cannot be resolved: Service %s/%s declares no ports", port, svc.Namespace, svc.Name)
+		}
+		return port, nil
+	}
+	for _, servicePort := range svc.Spec.Ports {
+		if servicePort.Name == port || strconv.Itoa(int(servicePort.Port)) == port {
+			return strconv.Itoa(int(servicePort.Port)), nil
+		}
+	}
+	if !annotated && len(svc.Spec.Ports) == 1 {
+		return strconv.Itoa(int(svc.Spec.Ports[0].Port)), nil
+	}
+	exposed := make([]string, 0, len(svc.Spec.Ports))
+	for _, servicePort := range svc.Spec.Ports {
+		if servicePort.Name != "" {
+			exposed = append(exposed, fmt.Sprintf("%s (%d)", servicePort.Name, servicePort.Port))
+		} else {
+			exposed = append(exposed, strconv.Itoa(int(servicePort.Port)))
+		}
+	}
+	return "", fmt.Errorf("port %q is not exposed by Service %s/%s; available ports: %s", port, svc.Namespace, svc.Name, strings.Join(exposed, ", "))
+}
+
+// defaultSchemePorts are the schemes a spec can be fetched with and their default ports.
+var defaultSchemePorts = map[string]string{"http": "80", "https": "443"}
+
+// specRequest returns the request fetching the spec of a collected API, with the TLS settings from its Service's
+// annotations. The insecure-skip-verify annotation is ignored unless spec.allowInsecureSkipVerify is set.
+func specRequest(instance *observabilityv1alpha1.OpenAPIAggregator, api collectedAPI) openapi.Request {
+	req := openapi.Request{URL: api.Info.URL}
+	if api.Service != nil {
+		req.TLSServerName = strings.TrimSpace(api.Service.Annotations[instance.Spec.TLSServerNameAnnotation])
+		req.InsecureSkipVerify = instance.Spec.AllowInsecureSkipVerify &&
+			api.Service.Annotations[instance.Spec.InsecureSkipVerifyAnnotation] == "true"
+	}
+	return req
+}
+
+// aggregatorServiceIndex indexes OpenAPIAggregators by the Services they can select, see aggregatorServiceIndexValues.
+const aggregatorServiceIndex = "openapiaggregator.serviceSelection"
+
+// allNamespacesIndexValue is the namespace part of index values for aggregators watching every namespace.
+const allNamespacesIndexValue = "*"
+
+// aggregatorServiceIndexValues returns the index values of an aggregator: "<namespace>/<swagger annotation key>"
+// for each watched namespace, or "<namespace>/" when the aggregator selects Services by label instead.
+func aggregatorServiceIndexValues(obj client.Object) []string {
+	instance, ok := obj.(*observabilityv1alpha1.OpenAPIAggregator)
+	if !ok {
+		return nil
+	}
+
+	annotationKey := instance.Spec.SwaggerAnnotation
+	if labelSelector(instance) != nil {
+		annotationKey = ""
+	}
+
+	namespaces, allNamespaces := watchedNamespaces(instance, instance.Namespace)
+	if allNamespaces {
+		namespaces = []string{allNamespacesIndexValue}
+	}
+	values := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		values = append(values, namespace+"/"+annotationKey)
+	}
+	return values
+}
+
+// serviceIndexLookups returns the aggregatorServiceIndex values under which aggregators selecting the Service are found.
+func serviceIndexLookups(svc *corev1.Service) []string {
+	var lookups []string
+	for _, namespace := range []string{svc.Namespace, allNamespacesIndexValue} {
+		lookups = append(lookups, namespace+"/")
+		for key, value := range svc.Annotations {
+			if value == "true" {
+				lookups = append(lookups, namespace+"/"+key)
+			}
+		}
+	}
+	return lookups
+}
+
+// findAggregatorsForService maps a Service to every OpenAPIAggregator that selects it.
+func (r *OpenAPIAggregatorReconciler) findAggregatorsForService(ctx context.Context, obj client.Object) []ctrl.Request {
+	svc, ok := obj.(*corev1.Service)
+	if !ok {
+		return nil
+	}
+
+	seen := map[types.NamespacedName]bool{}
+	var requests []ctrl.Request
+	for _, lookup := range serviceIndexLookups(svc) {
+		aggregators := &observabilityv1alpha1.OpenAPIAggregatorList{}
+		if err := r.List(ctx, aggregators, client.MatchingFields{aggregatorServiceIndex: lookup}); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list OpenAPIAggregators for service event", "service", svc.Name, "namespace", svc.Namespace)
+			continue
+		}
+		for i := range aggregators.Items {
+			aggregator := &aggregators.Items[i]
+			key := types.NamespacedName{Name: aggregator.Name, Namespace: aggregator.Namespace}
+			if seen[key] || !selectsService(aggregator, svc) {
+				continue
+			}
+			seen[key] = true
+			requests = append(requests, ctrl.Request{NamespacedName: key})
+		}
+	}
+	return requests
+}
+
+// serviceEventHandler enqueues the aggregators selecting a Service. Updates are mapped for both the old and
+// the new object, so that removing the annotation or a label still reconciles the aggregators that used to collect it.
+func (r *OpenAPIAggregatorReconciler) serviceEventHandler() handler.EventHandler {
+	enqueue := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[ctrl.Request], objs ...client.Object) {
+		for _, obj := range objs {
+			for _, req := range r.findAggregatorsForService(ctx, obj) {
+				q.Add(req)
+			}
+		}
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			enqueue(ctx, q, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+	}
+}
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OpenAPIAggregatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Fetcher == nil {
+		r.Fetcher = openapi.NewFetcher(openapi.FetcherOptions{})
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("openapiaggregator-controller")
+	}
+	r.Recorder = newThrottledRecorder(r.Recorder, eventRepeatInterval)
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &observabilityv1alpha1.OpenAPIAggregator{},
+		aggregatorServiceIndex, aggregatorServiceIndexValues); err != nil {
+		return err
+	}
+
+	// Status updates do not change the generation, so they do not trigger another reconcile.
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&observabilityv1alpha1.OpenAPIAggregator{}).
-		Watches(
-			&corev1.Service{},
-			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []ctrl.Request {
-				svc := obj.(*corev1.Service)
-				// 서비스에 swagger 관련 어노테이션이 있는 경우에만 리컨실레이션 트리거
-				if val, ok := svc.Annotations["openapi.aggregator.io/swagger"]; ok && val == "true" {
-					return []ctrl.Request{
-						{NamespacedName: types.NamespacedName{
-							Name:      "openapi-aggregator",
-							Namespace: svc.Namespace,
-						}},
-					}
-				}
-				return nil
-			}),
-		).
+		For(&observabilityv1alpha1.OpenAPIAggregator{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Service{}, r.serviceEventHandler()).
 		Complete(r)
 }
@@ -0,0 +1,724 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+// newService returns a Service with the given labels and annotations.
+func newService(namespace, name string, labels, annotations map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels, Annotations: annotations},
+	}
+}
+
+// serviceNames returns the namespace/name of each listed Service.
+func serviceNames(services corev1.ServiceList) []string {
+	names := make([]string, 0, len(services.Items))
+	for _, svc := range services.Items {
+		names = append(names, svc.Namespace+"/"+svc.Name)
+	}
+	return names
+}
+
+var _ = Describe("OpenAPIAggregator discovery", func() {
+	var instance *observabilityv1alpha1.OpenAPIAggregator
+
+	swagger := map[string]string{"openapi.aggregator.io/swagger": "true"}
+
+	BeforeEach(func() {
+		instance = &observabilityv1alpha1.OpenAPIAggregator{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis"},
+			Spec: observabilityv1alpha1.OpenAPIAggregatorSpec{
+				SwaggerAnnotation: "openapi.aggregator.io/swagger",
+			},
+		}
+	})
+
+	Context("listServices", func() {
+		// reconciler returns a reconciler whose client fails to list services in the failing namespaces.
+		reconciler := func(failing map[string]bool, objects ...client.Object) *OpenAPIAggregatorReconciler {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						listOptions := (&client.ListOptions{}).ApplyOptions(opts)
+						if failing[listOptions.Namespace] {
+							return fmt.Errorf("services is forbidden in %s", listOptions.Namespace)
+						}
+						return c.List(ctx, list, opts...)
+					},
+				}).Build()
+			return &OpenAPIAggregatorReconciler{Client: c}
+		}
+
+		It("merges the services of every watched namespace", func() {
+			instance.Spec.WatchNamespaces = []string{"team-a", "team-b", "team-a"}
+			r := reconciler(nil,
+				newService("team-a", "orders", nil, swagger),
+				newService("team-b", "payments", nil, swagger),
+				newService("team-c", "ignored", nil, swagger))
+
+			services, namespaceErrors, err := r.listServices(ctx, instance, instance.Namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(namespaceErrors).To(BeEmpty())
+			Expect(serviceNames(services)).To(ConsistOf("team-a/orders", "team-b/payments"))
+		})
+
+		It("reports namespaces that cannot be listed and keeps the others", func() {
+			instance.Spec.WatchNamespaces = []string{"team-a", "team-b"}
+			r := reconciler(map[string]bool{"team-b": true},
+				newService("team-a", "orders", nil, swagger),
+				newService("team-b", "payments", nil, swagger))
+
+			services, namespaceErrors, err := r.listServices(ctx, instance, instance.Namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceNames(services)).To(ConsistOf("team-a/orders"))
+			Expect(namespaceErrors).To(HaveLen(1))
+			Expect(namespaceErrors[0].Namespace).To(Equal("team-b"))
+			Expect(namespaceErrors[0].Error).To(ContainSubstring("forbidden"))
+		})
+
+		It("fails when no watched namespace can be listed", func() {
+			instance.Spec.WatchNamespaces = []string{"team-a", "team-b"}
+			r := reconciler(map[string]bool{"team-a": true, "team-b": true})
+
+			_, namespaceErrors, err := r.listServices(ctx, instance, instance.Namespace)
+			Expect(err).To(HaveOccurred())
+			Expect(namespaceErrors).To(HaveLen(2))
+		})
+	})
+
+	Context("label selection", func() {
+		BeforeEach(func() {
+			instance.Spec.LabelSelector = map[string]string{"api-tier": "public"}
+			instance.Spec.LabelSelectorExpressions = []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"payments", "orders"}},
+				{Key: "deprecated", Operator: metav1.LabelSelectorOpDoesNotExist},
+			}
+		})
+
+		DescribeTable("selectsService",
+			func(namespace string, labels, annotations map[string]string, selected bool) {
+				Expect(selectsService(instance, newService(namespace, "svc", labels, annotations))).To(Equal(selected))
+			},
+			Entry("matching labels without the annotation", "team-a",
+				map[string]string{"api-tier": "public", "team": "orders"}, nil, true),
+			Entry("matching labels with the annotation", "team-a",
+				map[string]string{"api-tier": "public", "team": "orders"}, swagger, true),
+			Entry("matching labels opted out by the annotation", "team-a",
+				map[string]string{"api-tier": "public", "team": "orders"}, map[string]string{"openapi.aggregator.io/swagger": "false"}, false),
+			Entry("a value outside the In expression", "team-a",
+				map[string]string{"api-tier": "public", "team": "billing"}, swagger, false),
+			Entry("a label excluded by the DoesNotExist expression", "team-a",
+				map[string]string{"api-tier": "public", "team": "orders", "deprecated": "true"}, nil, false),
+			Entry("labels in a namespace that is not watched", "team-b",
+				map[string]string{"api-tier": "public", "team": "orders"}, nil, false),
+		)
+
+		It("selects services by annotation only without a label selector", func() {
+			instance.Spec.LabelSelector = nil
+			instance.Spec.LabelSelectorExpressions = nil
+			Expect(selectsService(instance, newService("team-a", "svc", nil, swagger))).To(BeTrue())
+			Expect(selectsService(instance, newService("team-a", "svc", nil, map[string]string{"openapi.aggregator.io/swagger": "yes"}))).To(BeFalse())
+			Expect(selectsService(instance, newService("team-a", "svc", map[string]string{"api-tier": "public"}, nil))).To(BeFalse())
+		})
+
+		It("lists only the services matching the expressions", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				newService("team-a", "orders", map[string]string{"api-tier": "public", "team": "orders"}, nil),
+				newService("team-a", "billing", map[string]string{"api-tier": "public", "team": "billing"}, nil),
+				newService("team-a", "old", map[string]string{"api-tier": "public", "team": "orders", "deprecated": "true"}, nil),
+			).Build()
+			r := &OpenAPIAggregatorReconciler{Client: c}
+
+			services, _, err := r.listServices(ctx, instance, instance.Namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceNames(services)).To(ConsistOf("team-a/orders"))
+		})
+
+		It("rejects an invalid selector", func() {
+			instance.Spec.LabelSelectorExpressions[0].Operator = "Near"
+			r := &OpenAPIAggregatorReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()}
+
+			_, _, err := r.listServices(ctx, instance, instance.Namespace)
+			Expect(err).To(MatchError(ContainSubstring("invalid labelSelector")))
+		})
+
+		It("skips a listed service whose annotation opts out", func() {
+			r := &OpenAPIAggregatorReconciler{}
+			optedOut := newService("team-a", "orders", map[string]string{"api-tier": "public", "team": "orders"},
+				map[string]string{"openapi.aggregator.io/swagger": "false"})
+			Expect(r.processService(ctx, *optedOut, instance)).To(BeNil())
+		})
+	})
+
+	Context("service watch", func() {
+		var r *OpenAPIAggregatorReconciler
+
+		BeforeEach(func() {
+			byLabel := &observabilityv1alpha1.OpenAPIAggregator{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "by-label"},
+				Spec: observabilityv1alpha1.OpenAPIAggregatorSpec{
+					SwaggerAnnotation: "openapi.aggregator.io/swagger",
+					LabelSelectorExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"orders"}},
+					},
+				},
+			}
+			clusterWide := &observabilityv1alpha1.OpenAPIAggregator{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "platform", Name: "cluster-wide"},
+				Spec: observabilityv1alpha1.OpenAPIAggregatorSpec{
+					SwaggerAnnotation: "openapi.aggregator.io/swagger",
+					WatchNamespaces:   []string{"*"},
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+				WithObjects(byLabel, clusterWide).
+				WithIndex(&observabilityv1alpha1.OpenAPIAggregator{}, aggregatorServiceIndex, aggregatorServiceIndexValues).
+				Build()
+			r = &OpenAPIAggregatorReconciler{Client: c}
+		})
+
+		requestNames := func(requests []ctrl.Request) []string {
+			names := make([]string, 0, len(requests))
+			for _, req := range requests {
+				names = append(names, req.String())
+			}
+			return names
+		}
+
+		It("maps a service to every aggregator selecting it", func() {
+			svc := newService("team-a", "orders", map[string]string{"team": "orders"}, swagger)
+			Expect(requestNames(r.findAggregatorsForService(ctx, svc))).To(ConsistOf("team-a/by-label", "platform/cluster-wide"))
+		})
+
+		It("does not map a service opted out by its annotation", func() {
+			svc := newService("team-a", "orders", map[string]string{"team": "orders"}, map[string]string{"openapi.aggregator.io/swagger": "false"})
+			Expect(r.findAggregatorsForService(ctx, svc)).To(BeEmpty())
+		})
+
+		It("maps a service outside the selector only to annotation-based aggregators", func() {
+			svc := newService("team-a", "billing", map[string]string{"team": "billing"}, swagger)
+			Expect(requestNames(r.findAggregatorsForService(ctx, svc))).To(ConsistOf("platform/cluster-wide"))
+		})
+
+		It("enqueues the aggregators of the old object when a label is removed", func() {
+			queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[ctrl.Request]())
+			defer queue.ShutDown()
+
+			oldSvc := newService("team-a", "orders", map[string]string{"team": "orders"}, nil)
+			newSvc := newService("team-a", "orders", nil, nil)
+			r.serviceEventHandler().Update(ctx, event.UpdateEvent{ObjectOld: oldSvc, ObjectNew: newSvc}, queue)
+
+			Expect(queue.Len()).To(Equal(1))
+			req, _ := queue.Get()
+			Expect(req.String()).To(Equal("team-a/by-label"))
+		})
+	})
+
+	Context("retainedAPIs", func() {
+		BeforeEach(func() {
+			instance.Status.CollectedAPIs = []observabilityv1alpha1.APIInfo{
+				{Name: "orders", Namespace: "team-a", URL: "http://orders.team-a.svc.cluster.local:8080/v2/api-docs", Title: "Orders"},
+				{Name: "payments", Namespace: "team-b", URL: "http://payments.team-b.svc.cluster.local:8080/v2/api-docs",
+					Port: "8080", Path: "/v2/api-docs", Title: "Payments", HTTPStatus: 200, LastUpdated: "2025-01-01T00:00:00Z"},
+				{Name: "legacy", Namespace: "team-b", Error: "invalid openapi.aggregator.io/url annotation"},
+			}
+		})
+
+		It("keeps nothing when every namespace was listed", func() {
+			Expect(retainedAPIs(instance, nil)).To(BeEmpty())
+		})
+
+		It("keeps the previous APIs of namespaces that could not be listed", func() {
+			apis := retainedAPIs(instance, []observabilityv1alpha1.NamespaceError{{Namespace: "team-b", Error: "forbidden"}})
+			Expect(apis).To(HaveLen(2))
+
+			Expect(apis[0].Service).To(BeNil())
+			Expect(apis[0].Info.Name).To(Equal("payments"))
+			Expect(apis[0].Info.URL).To(Equal("http://payments.team-b.svc.cluster.local:8080/v2/api-docs"))
+			Expect(apis[0].Info.Port).To(Equal("8080"))
+			// The outcome of the fetch is determined again.
+			Expect(apis[0].Info.Title).To(BeEmpty())
+			Expect(apis[0].Info.HTTPStatus).To(BeZero())
+
+			Expect(apis[1].Info.Name).To(Equal("legacy"))
+			Expect(apis[1].Info.Error).To(ContainSubstring("invalid"))
+		})
+	})
+})
+
+var _ = Describe("Service ports", func() {
+	// servicePorts returns a Service exposing the given ports.
+	servicePorts := func(ports ...corev1.ServicePort) *corev1.Service {
+		svc := newService("team-a", "orders", nil, nil)
+		svc.Spec.Ports = ports
+		return svc
+	}
+	httpPort := corev1.ServicePort{Name: "http", Port: 8080}
+	metricsPort := corev1.ServicePort{Name: "metrics", Port: 9090}
+
+	DescribeTable("resolveServicePort",
+		func(svc *corev1.Service, port string, annotated bool, expected, expectedErr string) {
+			resolved, err := resolveServicePort(svc, port, annotated)
+			if expectedErr != "" {
+				Expect(err).To(MatchError(expectedErr))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).To(Equal(expected))
+		},
+		Entry("a named port", servicePorts(httpPort, metricsPort), "metrics", true, "9090", ""),
+		Entry("a numeric port the Service exposes", servicePorts(httpPort, metricsPort), "8080", true, "8080", ""),
+		Entry("a numeric port the Service does not expose", servicePorts(httpPort, metricsPort), "7070", true, "",
+			`port "7070" is not exposed by Service team-a/orders; available ports: http (8080), metrics (9090)`),
+		Entry("the single port of a Service when the port is not annotated", servicePorts(httpPort), "7070", false, "8080", ""),
+		Entry("an annotated port missing from a single-port Service",
+			servicePorts(corev1.ServicePort{Port: 8080}), "grpc", true, "",
+			`port "grpc" is not exposed by Service team-a/orders; available ports: 8080`),
+		Entry("a numeric port on a Service without ports", servicePorts(), "8080", true, "8080", ""),
+		Entry("a named port on a Service without ports", servicePorts(), "http", true, "",
+			`port "http" cannot be resolved: Service team-a/orders declares no ports`),
+	)
+
+	It("builds the spec URL from the resolved port", func() {
+		instance := &observabilityv1alpha1.OpenAPIAggregator{}
+		instance.Spec.PortAnnotation = "openapi.aggregator.io/port"
+		svc := servicePorts(httpPort, metricsPort)
+		svc.Annotations = map[string]string{"openapi.aggregator.io/port": "http"}
+		api := &observabilityv1alpha1.APIInfo{Path: "/v3/api-docs", Port: "http"}
+
+		Expect(setSpecURL(instance, svc, api)).To(Succeed())
+		Expect(api.Port).To(Equal("8080"))
+		Expect(api.URL).To(Equal("http://orders.team-a.svc.cluster.local:8080/v3/api-docs"))
+
+		api.Port = "grpc"
+		Expect(setSpecURL(instance, svc, api)).To(MatchError(ContainSubstring(`port "grpc" is not exposed`)))
+	})
+})
+
+var _ = Describe("Spec URL annotations", func() {
+	var (
+		instance *observabilityv1alpha1.OpenAPIAggregator
+		svc      *corev1.Service
+	)
+
+	BeforeEach(func() {
+		instance = &observabilityv1alpha1.OpenAPIAggregator{}
+		instance.Spec.SchemeAnnotation = "openapi.aggregator.io/scheme"
+		instance.Spec.URLAnnotation = "openapi.aggregator.io/url"
+		instance.Spec.TLSServerNameAnnotation = "openapi.aggregator.io/tls-server-name"
+		instance.Spec.InsecureSkipVerifyAnnotation = "openapi.aggregator.io/insecure-skip-verify"
+		svc = newService("team-a", "orders", nil, map[string]string{})
+		svc.Spec.Ports = []corev1.ServicePort{{Name: "http", Port: 8080}}
+	})
+
+	DescribeTable("setSpecURL with the URL annotation",
+		func(allow bool, override, expectedURL, expectedPort, expectedErr string) {
+			instance.Spec.AllowURLOverride = allow
+			svc.Annotations["openapi.aggregator.io/url"] = override
+			api := &observabilityv1alpha1.APIInfo{Path: "/v2/api-docs", Port: "http"}
+
+			err := setSpecURL(instance, svc, api)
+			if expectedErr != "" {
+				Expect(err).To(MatchError(expectedErr))
+				Expect(api.URL).To(BeEmpty())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.URL).To(Equal(expectedURL))
+			Expect(api.Port).To(Equal(expectedPort))
+		},
+		Entry("an https URL when allowed", true, "https://orders.internal/openapi.json",
+			"https://orders.internal/openapi.json", "443", ""),
+		Entry("an http URL with a port when allowed", true, "http://10.0.0.7:9000/v3/api-docs",
+			"http://10.0.0.7:9000/v3/api-docs", "9000", ""),
+		Entry("a URL when not allowed", false, "http://169.254.169.254/latest/meta-data", "", "",
+			"openapi.aggregator.io/url annotation is not honored: spec.allowURLOverride is not set"),
+		Entry("a file URL", true, "file:///var/run/secrets/kubernetes.io/serviceaccount/token", "", "",
+			`invalid openapi.aggregator.io/url annotation "file:///var/run/secrets/kubernetes.io/serviceaccount/token": must be an absolute http or https URL`),
+		Entry("a gopher URL", true, "gopher://orders.internal:70/", "", "",
+			`invalid openapi.aggregator.io/url annotation "gopher://orders.internal:70/": must be an absolute http or https URL`),
+		Entry("a relative URL", true, "/openapi.json", "", "",
+			`invalid openapi.aggregator.io/url annotation "/openapi.json": must be an absolute http or https URL`),
+	)
+
+	It("rejects a scheme annotation other than http or https", func() {
+		svc.Annotations["openapi.aggregator.io/scheme"] = "ftp"
+		api := &observabilityv1alpha1.APIInfo{Path: "/v2/api-docs", Port: "http"}
+		Expect(setSpecURL(instance, svc, api)).To(MatchError(`invalid openapi.aggregator.io/scheme annotation "ftp": must be http or https`))
+	})
+
+	DescribeTable("specRequest",
+		func(allow bool, annotations map[string]string, expected openapi.Request) {
+			instance.Spec.AllowInsecureSkipVerify = allow
+			svc.Annotations = annotations
+			api := collectedAPI{Info: observabilityv1alpha1.APIInfo{URL: "https://orders.team-a.svc.cluster.local:8443/v2/api-docs"}, Service: svc}
+			Expect(specRequest(instance, api)).To(Equal(expected))
+		},
+		Entry("no TLS annotations", false, nil,
+			openapi.Request{URL: "https://orders.team-a.svc.cluster.local:8443/v2/api-docs"}),
+		Entry("a TLS server name", false, map[string]string{"openapi.aggregator.io/tls-server-name": " orders.example.com "},
+			openapi.Request{URL: "https://orders.team-a.svc.cluster.local:8443/v2/api-docs", TLSServerName: "orders.example.com"}),
+		Entry("insecure-skip-verify when allowed", true, map[string]string{"openapi.aggregator.io/insecure-skip-verify": "true"},
+			openapi.Request{URL: "https://orders.team-a.svc.cluster.local:8443/v2/api-docs", InsecureSkipVerify: true}),
+		Entry("insecure-skip-verify when not allowed", false, map[string]string{"openapi.aggregator.io/insecure-skip-verify": "true"},
+			openapi.Request{URL: "https://orders.team-a.svc.cluster.local:8443/v2/api-docs"}),
+		Entry("insecure-skip-verify other than true", true, map[string]string{"openapi.aggregator.io/insecure-skip-verify": "yes"},
+			openapi.Request{URL: "https://orders.team-a.svc.cluster.local:8443/v2/api-docs"}),
+	)
+
+	It("builds a request without TLS settings for an API without a Service", func() {
+		instance.Spec.AllowInsecureSkipVerify = true
+		api := collectedAPI{Info: observabilityv1alpha1.APIInfo{URL: "http://orders.team-a.svc.cluster.local:8080/v2/api-docs"}}
+		Expect(specRequest(instance, api)).To(Equal(openapi.Request{URL: api.Info.URL}))
+	})
+})
+
+var _ = Describe("Stored specs", func() {
+	const snapshot = `{"openapi":"3.0.3","info":{"title":"Orders","version":"1.0.0"},"paths":{"/orders":{` +
+		`"get":{"responses":{"200":{"description":"OK"}}},"delete":{"responses":{"204":{"description":"Deleted"}}}}}}`
+
+	// failedAPI returns an API whose spec could not be fetched, allowing the given methods.
+	failedAPI := func(allowedMethods ...string) collectedAPI {
+		return collectedAPI{Info: observabilityv1alpha1.APIInfo{
+			Name: "orders", Namespace: "team-a", Error: "connection refused", AllowedMethods: allowedMethods,
+		}}
+	}
+
+	// operations returns the methods of the /orders path of a stored spec.
+	operations := func(spec []byte) []string {
+		document, err := openapi.Parse(spec)
+		Expect(err).NotTo(HaveOccurred())
+		paths, _ := document.Content["paths"].(map[string]interface{})
+		item, _ := paths["/orders"].(map[string]interface{})
+		var methods []string
+		for method := range item {
+			methods = append(methods, method)
+		}
+		return methods
+	}
+
+	It("keeps the previous snapshot as is when the allowed methods still allow it", func() {
+		spec, err := storedSpec(failedAPI("GET", "DELETE"), configMapEntry{Spec: json.RawMessage(snapshot)}, outputEntry{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(spec)).To(Equal(snapshot))
+
+		spec, err = storedSpec(failedAPI(), configMapEntry{Spec: json.RawMessage(snapshot)}, outputEntry{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(spec)).To(Equal(snapshot))
+	})
+
+	It("prunes the previous snapshot with the current allowed methods", func() {
+		spec, err := storedSpec(failedAPI("GET"), configMapEntry{Spec: json.RawMessage(snapshot)}, outputEntry{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operations(spec)).To(ConsistOf("get"))
+	})
+
+	It("prunes a compressed snapshot with the current allowed methods", func() {
+		compressed, err := gzipBytes([]byte(snapshot))
+		Expect(err).NotTo(HaveOccurred())
+		previousEntry := configMapEntry{SpecEncoding: gzipEncoding, SpecKey: "team-a.orders.gz"}
+		previous := outputEntry{BinaryData: map[string][]byte{"team-a.orders.gz": compressed}}
+
+		spec, err := storedSpec(failedAPI("DELETE"), previousEntry, previous)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operations(spec)).To(ConsistOf("delete"))
+	})
+
+	It("drops a snapshot that cannot be pruned", func() {
+		spec, err := storedSpec(failedAPI("GET"), configMapEntry{Spec: json.RawMessage(`{"openapi":`)}, outputEntry{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec).To(BeNil())
+	})
+})
+
+var _ = Describe("Aggregator status", func() {
+	// expectedCondition is the status and reason a condition is expected to have.
+	type expectedCondition struct {
+		status metav1.ConditionStatus
+		reason string
+	}
+
+	var instance *observabilityv1alpha1.OpenAPIAggregator
+
+	BeforeEach(func() {
+		instance = &observabilityv1alpha1.OpenAPIAggregator{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis", Generation: 4}}
+		instance.Status.ObservedGeneration = 3
+	})
+
+	// expectConditions checks the conditions of a status and that they observed the instance's generation.
+	expectConditions := func(status observabilityv1alpha1.OpenAPIAggregatorStatus, expected map[string]expectedCondition) {
+		Expect(status.Conditions).To(HaveLen(len(expected)))
+		for conditionType, want := range expected {
+			condition := apimeta.FindStatusCondition(status.Conditions, conditionType)
+			Expect(condition).NotTo(BeNil(), conditionType)
+			Expect(condition.Status).To(Equal(want.status), conditionType)
+			Expect(condition.Reason).To(Equal(want.reason), conditionType)
+			Expect(condition.ObservedGeneration).To(Equal(int64(4)), conditionType)
+		}
+	}
+
+	DescribeTable("after collecting the APIs",
+		func(apiErrors []string, namespaceErrors []observabilityv1alpha1.NamespaceError, syncErr error,
+			discovered, healthy, failed int32, expected map[string]expectedCondition) {
+			var apis []collectedAPI
+			for i, apiError := range apiErrors {
+				apis = append(apis, collectedAPI{Info: observabilityv1alpha1.APIInfo{
+					Namespace: "team-a", Name: fmt.Sprintf("api-%d", i), Error: apiError,
+				}})
+			}
+
+			status := aggregatorStatus(instance, apis, namespaceErrors, nil)
+			setSyncConditions(&status, instance.Generation, syncErr)
+
+			Expect(status.ObservedGeneration).To(Equal(int64(4)))
+			Expect(status.DiscoveredAPIs).To(Equal(discovered))
+			Expect(status.HealthyAPIs).To(Equal(healthy))
+			Expect(status.FailedAPIs).To(Equal(failed))
+			Expect(status.NamespaceErrors).To(Equal(namespaceErrors))
+			Expect(status.LastSyncTime != nil).To(Equal(syncErr == nil))
+			expectConditions(status, expected)
+		},
+		Entry("success", []string{"", ""}, nil, nil, int32(2), int32(2), int32(0), map[string]expectedCondition{
+			ReadyCondition:           {metav1.ConditionTrue, "Reconciled"},
+			DiscoveringCondition:     {metav1.ConditionFalse, "DiscoveryComplete"},
+			ConfigMapSyncedCondition: {metav1.ConditionTrue, "ConfigMapSynced"},
+			DegradedCondition:        {metav1.ConditionFalse, "AllAPIsHealthy"},
+		}),
+		Entry("some specs failing", []string{"", "connection refused", "HTTP 500"}, nil, nil,
+			int32(3), int32(1), int32(2), map[string]expectedCondition{
+				ReadyCondition:           {metav1.ConditionTrue, "Reconciled"},
+				DiscoveringCondition:     {metav1.ConditionFalse, "DiscoveryComplete"},
+				ConfigMapSyncedCondition: {metav1.ConditionTrue, "ConfigMapSynced"},
+				DegradedCondition:        {metav1.ConditionTrue, "SpecCollectionFailed"},
+			}),
+		Entry("some namespaces failing to list", []string{""},
+			[]observabilityv1alpha1.NamespaceError{{Namespace: "team-b", Error: "forbidden"}}, nil,
+			int32(1), int32(1), int32(0), map[string]expectedCondition{
+				ReadyCondition:           {metav1.ConditionTrue, "Reconciled"},
+				DiscoveringCondition:     {metav1.ConditionFalse, "DiscoveryComplete"},
+				ConfigMapSyncedCondition: {metav1.ConditionTrue, "ConfigMapSynced"},
+				DegradedCondition:        {metav1.ConditionTrue, "NamespaceListFailed"},
+			}),
+		Entry("the ConfigMap failing to sync", []string{""}, nil, fmt.Errorf("timeout"),
+			int32(1), int32(1), int32(0), map[string]expectedCondition{
+				ReadyCondition:           {metav1.ConditionFalse, "ConfigMapSyncFailed"},
+				DiscoveringCondition:     {metav1.ConditionFalse, "DiscoveryComplete"},
+				ConfigMapSyncedCondition: {metav1.ConditionFalse, "ConfigMapSyncFailed"},
+				DegradedCondition:        {metav1.ConditionFalse, "AllAPIsHealthy"},
+			}),
+		Entry("the ConfigMap owned by someone else", []string{""}, nil, fmt.Errorf("apis-specs: %w", errConfigMapConflict),
+			int32(1), int32(1), int32(0), map[string]expectedCondition{
+				ReadyCondition:           {metav1.ConditionFalse, "ConfigMapConflict"},
+				DiscoveringCondition:     {metav1.ConditionFalse, "DiscoveryComplete"},
+				ConfigMapSyncedCondition: {metav1.ConditionFalse, "ConfigMapConflict"},
+				DegradedCondition:        {metav1.ConditionFalse, "AllAPIsHealthy"},
+			}),
+	)
+
+	It("keeps the counters when no namespace can be listed", func() {
+		instance.Status.DiscoveredAPIs = 2
+		instance.Status.HealthyAPIs = 2
+		status := instance.Status.DeepCopy()
+		status.ObservedGeneration = instance.Generation
+
+		setListFailedConditions(status, instance.Generation, fmt.Errorf("forbidden"))
+
+		Expect(status.ObservedGeneration).To(Equal(int64(4)))
+		Expect(status.DiscoveredAPIs).To(Equal(int32(2)))
+		Expect(status.HealthyAPIs).To(Equal(int32(2)))
+		expectConditions(*status, map[string]expectedCondition{
+			ReadyCondition:       {metav1.ConditionFalse, "ListServicesFailed"},
+			DiscoveringCondition: {metav1.ConditionFalse, "ListServicesFailed"},
+			DegradedCondition:    {metav1.ConditionTrue, "ListServicesFailed"},
+		})
+	})
+
+	It("carries over conditions and keeps their transition time while unchanged", func() {
+		status := aggregatorStatus(instance, []collectedAPI{{Info: observabilityv1alpha1.APIInfo{Name: "orders"}}}, nil, nil)
+		setSyncConditions(&status, instance.Generation, nil)
+		instance.Status = status
+		transition := apimeta.FindStatusCondition(status.Conditions, ReadyCondition).LastTransitionTime
+
+		next := aggregatorStatus(instance, []collectedAPI{{Info: observabilityv1alpha1.APIInfo{Name: "orders"}}}, nil, nil)
+		setSyncConditions(&next, instance.Generation, nil)
+		Expect(apimeta.FindStatusCondition(next.Conditions, ReadyCondition).LastTransitionTime).To(Equal(transition))
+	})
+})
+
+var _ = Describe("Output ConfigMap", func() {
+	var (
+		r        *OpenAPIAggregatorReconciler
+		recorder *record.FakeRecorder
+		instance *observabilityv1alpha1.OpenAPIAggregator
+		apis     []collectedAPI
+	)
+
+	// controlledBy returns a ConfigMap controlled by the given aggregator, with the given labels.
+	controlledBy := func(owner *observabilityv1alpha1.OpenAPIAggregator, name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "team-a", Name: name, Labels: labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: observabilityv1alpha1.GroupVersion.String(), Kind: "OpenAPIAggregator",
+				Name: owner.Name, UID: owner.UID, Controller: &[]bool{true}[0],
+			}},
+		}, Data: map[string]string{"team-a.old": "{}"}}
+	}
+
+	// exists reports whether a ConfigMap exists in the aggregator's namespace.
+	exists := func(name string) bool {
+		err := r.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: name}, &corev1.ConfigMap{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	// withObjects replaces the reconciler's client with one holding the given objects.
+	withObjects := func(objects ...client.Object) {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+	}
+
+	BeforeEach(func() {
+		instance = &observabilityv1alpha1.OpenAPIAggregator{
+			TypeMeta:   metav1.TypeMeta{APIVersion: observabilityv1alpha1.GroupVersion.String(), Kind: "OpenAPIAggregator"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis", UID: types.UID("aggregator-uid")},
+		}
+		apis = []collectedAPI{{Info: observabilityv1alpha1.APIInfo{Namespace: "team-a", Name: "orders"}}}
+		recorder = record.NewFakeRecorder(100)
+		r = &OpenAPIAggregatorReconciler{Recorder: recorder}
+		withObjects()
+	})
+
+	Context("owned by something else", func() {
+		It("is not overwritten when another aggregator controls it", func() {
+			other := &observabilityv1alpha1.OpenAPIAggregator{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: types.UID("other-uid")}}
+			withObjects(controlledBy(other, "apis-specs", nil))
+
+			_, err := r.createOrUpdateConfigMap(ctx, "team-a", instance, apis, nil)
+			Expect(err).To(MatchError(errConfigMapConflict))
+			Expect(err).To(MatchError(ContainSubstring("apis-specs is controlled by OpenAPIAggregator other")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("ConfigMapConflict")))
+
+			cm := &corev1.ConfigMap{}
+			Expect(r.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "apis-specs"}, cm)).To(Succeed())
+			Expect(cm.Data).To(Equal(map[string]string{"team-a.old": "{}"}))
+		})
+
+		It("is not overwritten when nothing controls it", func() {
+			withObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis-specs"}})
+
+			_, err := r.createOrUpdateConfigMap(ctx, "team-a", instance, apis, nil)
+			Expect(err).To(MatchError(errConfigMapConflict))
+			Expect(err).To(MatchError(ContainSubstring("controlled by nothing")))
+		})
+	})
+
+	Context("renamed", func() {
+		It("deletes the previous output, its history and its shards", func() {
+			withObjects(
+				controlledBy(instance, "old-specs", nil),
+				controlledBy(instance, "old-specs-history", nil),
+				controlledBy(instance, "old-specs-0", map[string]string{aggregatorUIDLabel: "aggregator-uid", shardOfLabel: "old-specs"}),
+			)
+			instance.Status.Output.ConfigMapName = "old-specs"
+			instance.Spec.Output.ConfigMapName = "new-specs"
+
+			output, err := r.createOrUpdateConfigMap(ctx, "team-a", instance, apis, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.ConfigMapName).To(Equal("new-specs"))
+			Expect(exists("new-specs")).To(BeTrue())
+			Expect(exists("old-specs")).To(BeFalse())
+			Expect(exists("old-specs-history")).To(BeFalse())
+			Expect(exists("old-specs-0")).To(BeFalse())
+		})
+
+		It("keeps a previous ConfigMap it does not control", func() {
+			withObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "old-specs"}})
+			instance.Status.Output.ConfigMapName = "old-specs"
+
+			output, err := r.createOrUpdateConfigMap(ctx, "team-a", instance, apis, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.ConfigMapName).To(Equal("apis-specs"))
+			Expect(exists("old-specs")).To(BeTrue())
+		})
+	})
+
+	Context("from before spec.output.configMapName", func() {
+		It("keeps writing the legacy ConfigMap the aggregator controls", func() {
+			withObjects(controlledBy(instance, legacyConfigMapName, nil))
+
+			Expect(r.adoptLegacyOutput(ctx, instance)).To(Succeed())
+			Expect(instance.Status.Output.ConfigMapName).To(Equal(legacyConfigMapName))
+			output, err := r.createOrUpdateConfigMap(ctx, "team-a", instance, apis, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.ConfigMapName).To(Equal(legacyConfigMapName))
+			Expect(exists("apis-specs")).To(BeFalse())
+
+			By("moving the output once spec.output.configMapName is set")
+			instance.Spec.Output.ConfigMapName = "apis-specs"
+			output, err = r.createOrUpdateConfigMap(ctx, "team-a", instance, apis, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.ConfigMapName).To(Equal("apis-specs"))
+			Expect(exists(legacyConfigMapName)).To(BeFalse())
+		})
+
+		It("uses the default name without a legacy ConfigMap of its own", func() {
+			other := &observabilityv1alpha1.OpenAPIAggregator{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: types.UID("other-uid")}}
+			withObjects(controlledBy(other, legacyConfigMapName, nil))
+
+			Expect(r.adoptLegacyOutput(ctx, instance)).To(Succeed())
+			Expect(outputConfigMapName(instance)).To(Equal("apis-specs"))
+
+			withObjects()
+			Expect(r.adoptLegacyOutput(ctx, instance)).To(Succeed())
+			Expect(outputConfigMapName(instance)).To(Equal("apis-specs"))
+		})
+
+		It("does not look for the legacy ConfigMap once the output is reported", func() {
+			withObjects(controlledBy(instance, legacyConfigMapName, nil))
+			instance.Status.Output.ConfigMapName = "apis-specs"
+
+			Expect(r.adoptLegacyOutput(ctx, instance)).To(Succeed())
+			Expect(outputConfigMapName(instance)).To(Equal("apis-specs"))
+		})
+	})
+})
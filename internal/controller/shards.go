@@ -0,0 +1,410 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+)
+
+const (
+	// maxConfigMapDataBytes is the data size above which the output is split into shards.
+	// It leaves headroom below the 1 MiB object size limit for metadata.
+	maxConfigMapDataBytes = 900 * 1024
+
+	// legacyConfigMapName is the output ConfigMap name of aggregators from before spec.output.configMapName.
+	legacyConfigMapName = "openapi-specs"
+
+	// shardIndexKey is the key of the shard index in the output ConfigMap of sharded output.
+	shardIndexKey = "index.json"
+
+	// aggregatorUIDLabel marks the shards written by an aggregator, so obsolete ones can be found and deleted.
+	aggregatorUIDLabel = "observability.aggregator.io/aggregator-uid"
+
+	// shardOfLabel identifies the output ConfigMap a shard belongs to, see shardOfLabelValue.
+	shardOfLabel = "observability.aggregator.io/shard-of"
+)
+
+// errConfigMapConflict is returned when an output ConfigMap is controlled by something other than the aggregator.
+var errConfigMapConflict = goerrors.New("ConfigMap is not controlled by this OpenAPIAggregator")
+
+// shardIndex is stored under shardIndexKey in the output ConfigMap when the output is sharded.
+type shardIndex struct {
+	// Shards lists the ConfigMaps holding the entries, in order
+	Shards []string `json:"shards"`
+
+	// Keys maps each entry key to the shard holding it
+	Keys map[string]string `json:"keys"`
+}
+
+// outputEntry holds the ConfigMap keys stored for one API, or for the merged document. The keys of an
+// entry are always placed in the same shard.
+type outputEntry struct {
+	// Data holds the keys stored in the ConfigMap's data
+	Data map[string]string
+
+	// BinaryData holds the keys stored in the ConfigMap's binaryData
+	BinaryData map[string][]byte
+}
+
+// size returns the number of bytes taken by the keys and values of the entry.
+func (e outputEntry) size() int {
+	size := 0
+	for key, value := range e.Data {
+		size += len(key) + len(value)
+	}
+	for key, value := range e.BinaryData {
+		size += len(key) + len(value)
+	}
+	return size
+}
+
+// outputEntryKey returns the key of the entry a data or binaryData key belongs to.
+func outputEntryKey(key string) string {
+	return strings.TrimSuffix(key, compressedKeySuffix)
+}
+
+// addOutputKeys adds the keys of a ConfigMap to entries, grouped by entry, and calls placed with each entry key.
+func addOutputKeys(entries map[string]outputEntry, cm *corev1.ConfigMap, placed func(string)) {
+	for key, value := range cm.Data {
+		entry := entries[key]
+		if entry.Data == nil {
+			entry.Data = map[string]string{}
+		}
+		entry.Data[key] = value
+		entries[key] = entry
+		placed(key)
+	}
+	for key, value := range cm.BinaryData {
+		entryKey := outputEntryKey(key)
+		entry := entries[entryKey]
+		if entry.BinaryData == nil {
+			entry.BinaryData = map[string][]byte{}
+		}
+		entry.BinaryData[key] = value
+		entries[entryKey] = entry
+		placed(entryKey)
+	}
+}
+
+// flattenOutput returns the data and binaryData of a ConfigMap holding entries.
+func flattenOutput(entries map[string]outputEntry) (map[string]string, map[string][]byte) {
+	data := map[string]string{}
+	var binaryData map[string][]byte
+	for _, entry := range entries {
+		for key, value := range entry.Data {
+			data[key] = value
+		}
+		for key, value := range entry.BinaryData {
+			if binaryData == nil {
+				binaryData = map[string][]byte{}
+			}
+			binaryData[key] = value
+		}
+	}
+	return data, binaryData
+}
+
+// shardName returns the name of the n-th shard of an output ConfigMap.
+func shardName(configMapName string, n int) string {
+	return fmt.Sprintf("%s-%d", configMapName, n)
+}
+
+// shardOfLabelValue returns the shardOfLabel value of the shards of an output ConfigMap: its name, or for a
+// name longer than a label value can be, a prefix of the name followed by a hash of the whole name.
+func shardOfLabelValue(configMapName string) string {
+	if len(configMapName) <= validation.LabelValueMaxLength {
+		return configMapName
+	}
+	sum := sha256.Sum256([]byte(configMapName))
+	hash := hex.EncodeToString(sum[:8])
+	prefix := strings.TrimRight(configMapName[:validation.LabelValueMaxLength-len(hash)-1], "-.")
+	return prefix + "-" + hash
+}
+
+// planShards distributes entries over shards holding at most limit bytes each. An entry stays in the
+// shard it was in before while that shard has room, so shards whose entries did not change are not
+// rewritten; the other entries are placed, in key order, into the first shard with room. An entry
+// larger than limit, which createOrUpdateConfigMap does not store, would get a shard of its own.
+func planShards(entries map[string]outputEntry, previous map[string]int, limit int) []map[string]outputEntry {
+	var shards []map[string]outputEntry
+	var sizes []int
+	place := func(n int, key string) {
+		for len(shards) <= n {
+			shards = append(shards, map[string]outputEntry{})
+			sizes = append(sizes, 0)
+		}
+		shards[n][key] = entries[key]
+		sizes[n] += entries[key].size()
+	}
+	fits := func(n, size int) bool {
+		return n >= len(shards) || sizes[n] == 0 || sizes[n]+size <= limit
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var unplaced []string
+	for _, key := range keys {
+		if n, ok := previous[key]; ok && fits(n, entries[key].size()) {
+			place(n, key)
+			continue
+		}
+		unplaced = append(unplaced, key)
+	}
+	for _, key := range unplaced {
+		n := 0
+		for !fits(n, entries[key].size()) {
+			n++
+		}
+		place(n, key)
+	}
+
+	// Empty shards are kept in between so that later shards keep their names, but not at the end.
+	for len(shards) > 0 && len(shards[len(shards)-1]) == 0 {
+		shards = shards[:len(shards)-1]
+	}
+	return shards
+}
+
+// readOutput returns the entries currently stored for an output ConfigMap, following its shard index,
+// and the shard number of each entry if the output is sharded.
+func (r *OpenAPIAggregatorReconciler) readOutput(ctx context.Context, namespace, configMapName string) (map[string]outputEntry, map[string]int, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: configMapName}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	entries := map[string]outputEntry{}
+	rawIndex, sharded := cm.Data[shardIndexKey]
+	if !sharded {
+		addOutputKeys(entries, cm, func(string) {})
+		return entries, nil, nil
+	}
+
+	var index shardIndex
+	if err := json.Unmarshal([]byte(rawIndex), &index); err != nil {
+		// A corrupt index only loses the placement; everything is rewritten.
+		return nil, nil, nil
+	}
+	placement := map[string]int{}
+	for n, name := range index.Shards {
+		shard := &corev1.ConfigMap{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, shard); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		addOutputKeys(entries, shard, func(key string) { placement[key] = n })
+	}
+	return entries, placement, nil
+}
+
+// writeOutput stores the output entries of an aggregator, in the output ConfigMap itself if they fit
+// or spread over shards listed in an index otherwise, and deletes shards that are no longer used.
+func (r *OpenAPIAggregatorReconciler) writeOutput(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator,
+	configMapName string, entries map[string]outputEntry, placement map[string]int) (observabilityv1alpha1.OutputStatus, error) {
+	output := observabilityv1alpha1.OutputStatus{ConfigMapName: configMapName}
+	if configMapDataSize(entries) <= maxConfigMapDataBytes {
+		data, binaryData := flattenOutput(entries)
+		if err := r.writeConfigMap(ctx, instance, configMapName, data, binaryData, nil); err != nil {
+			return output, err
+		}
+		return output, r.deleteObsoleteShards(ctx, instance, configMapName, nil)
+	}
+
+	index := shardIndex{Keys: map[string]string{}}
+	labels := map[string]string{aggregatorUIDLabel: string(instance.UID), shardOfLabel: shardOfLabelValue(configMapName)}
+	for n, shardEntries := range planShards(entries, placement, maxConfigMapDataBytes) {
+		name := shardName(configMapName, n)
+		data, binaryData := flattenOutput(shardEntries)
+		if err := r.writeConfigMap(ctx, instance, name, data, binaryData, labels); err != nil {
+			return output, err
+		}
+		index.Shards = append(index.Shards, name)
+		for key := range data {
+			index.Keys[key] = name
+		}
+		for key := range binaryData {
+			index.Keys[key] = name
+		}
+	}
+
+	// The index is written last, so it never refers to shards that do not exist yet.
+	indexJSON, err := json.Marshal(index)
+	if err != nil {
+		return output, err
+	}
+	if err := r.writeConfigMap(ctx, instance, configMapName, map[string]string{shardIndexKey: string(indexJSON)}, nil, nil); err != nil {
+		return output, err
+	}
+	output.Shards = index.Shards
+	return output, r.deleteObsoleteShards(ctx, instance, configMapName, index.Shards)
+}
+
+// writeConfigMap creates a ConfigMap owned by the aggregator, or updates it if its data or labels changed.
+func (r *OpenAPIAggregatorReconciler) writeConfigMap(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator,
+	name string, data map[string]string, binaryData map[string][]byte, labels map[string]string) error {
+	logger := log.FromContext(ctx)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: instance.APIVersion,
+					Kind:       instance.Kind,
+					Name:       instance.Name,
+					UID:        instance.UID,
+					Controller: &[]bool{true}[0],
+				},
+			},
+		},
+		Data:       data,
+		BinaryData: binaryData,
+	}
+
+	foundCm := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, foundCm)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && !metav1.IsControlledBy(foundCm, instance) {
+		owner := "nothing"
+		if ref := metav1.GetControllerOf(foundCm); ref != nil {
+			owner = fmt.Sprintf("%s %s", ref.Kind, ref.Name)
+		}
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "ConfigMapConflict",
+			"ConfigMap %s is controlled by %s; set spec.output.configMapName to another name", name, owner)
+		return fmt.Errorf("%w: %s is controlled by %s", errConfigMapConflict, name, owner)
+	}
+	if errors.IsNotFound(err) {
+		logger.Info("ConfigMap not found, creating new one", "ConfigMap.Name", cm.Name, "ConfigMap.Namespace", cm.Namespace)
+		if err := r.Client.Create(ctx, cm); err != nil {
+			return err
+		}
+		configMapWrites.WithLabelValues(instance.Namespace, instance.Name, "create").Inc()
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ConfigMapCreated", "Created ConfigMap %s with %d keys", cm.Name, len(data)+len(binaryData))
+		return nil
+	}
+	// Labels set by others are kept; only ours are added
+	labelsChanged := false
+	for key, value := range labels {
+		if foundCm.Labels[key] != value {
+			labelsChanged = true
+		}
+	}
+	// Only update if data has changed
+	if !r.isConfigMapDataEqual(foundCm, cm) || labelsChanged {
+		foundCm.Data = cm.Data // Update data
+		foundCm.BinaryData = cm.BinaryData
+		if foundCm.Labels == nil && len(labels) > 0 {
+			foundCm.Labels = map[string]string{}
+		}
+		for key, value := range labels {
+			foundCm.Labels[key] = value
+		}
+		if err := r.Client.Update(ctx, foundCm); err != nil {
+			return err
+		}
+		configMapWrites.WithLabelValues(instance.Namespace, instance.Name, "update").Inc()
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ConfigMapUpdated", "Rewrote ConfigMap %s with %d keys", cm.Name, len(data)+len(binaryData))
+	}
+	return nil // No update needed
+}
+
+// deleteOutput deletes an output ConfigMap of the aggregator, its shards and its spec history.
+func (r *OpenAPIAggregatorReconciler) deleteOutput(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator, configMapName string) error {
+	if err := r.deleteControlledConfigMap(ctx, instance, configMapName, "Deleted previous output ConfigMap %s"); err != nil {
+		return err
+	}
+	if err := r.deleteControlledConfigMap(ctx, instance, historyConfigMapName(configMapName), "Deleted previous spec history ConfigMap %s"); err != nil {
+		return err
+	}
+	return r.deleteObsoleteShards(ctx, instance, configMapName, nil)
+}
+
+// deleteControlledConfigMap deletes a ConfigMap if it exists and is controlled by the aggregator,
+// recording an event with the given message.
+func (r *OpenAPIAggregatorReconciler) deleteControlledConfigMap(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator,
+	name, messageFmt string) error {
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: name}, cm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(cm, instance) {
+		return nil
+	}
+	if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ConfigMapDeleted", messageFmt, cm.Name)
+	return nil
+}
+
+// deleteObsoleteShards deletes the shards of an output ConfigMap that are not in keep.
+func (r *OpenAPIAggregatorReconciler) deleteObsoleteShards(ctx context.Context, instance *observabilityv1alpha1.OpenAPIAggregator,
+	configMapName string, keep []string) error {
+	var shards corev1.ConfigMapList
+	if err := r.List(ctx, &shards, client.InNamespace(instance.Namespace),
+		client.MatchingLabels{aggregatorUIDLabel: string(instance.UID), shardOfLabel: shardOfLabelValue(configMapName)}); err != nil {
+		return err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[name] = true
+	}
+	for i := range shards.Items {
+		shard := &shards.Items[i]
+		if kept[shard.Name] {
+			continue
+		}
+		if err := r.Delete(ctx, shard); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ConfigMapDeleted", "Deleted obsolete shard ConfigMap %s", shard.Name)
+	}
+	return nil
+}
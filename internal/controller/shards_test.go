@@ -0,0 +1,261 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+// sizedEntry returns an output entry holding a single key that takes size bytes.
+func sizedEntry(key string, size int) outputEntry {
+	return outputEntry{Data: map[string]string{key: strings.Repeat("x", size-len(key))}}
+}
+
+// sizedEntries returns an output entry of the given size for each key.
+func sizedEntries(sizes map[string]int) map[string]outputEntry {
+	entries := make(map[string]outputEntry, len(sizes))
+	for key, size := range sizes {
+		entries[key] = sizedEntry(key, size)
+	}
+	return entries
+}
+
+// shardKeys returns the sorted entry keys of each shard.
+func shardKeys(shards []map[string]outputEntry) [][]string {
+	keys := make([][]string, 0, len(shards))
+	for _, shard := range shards {
+		shardKeys := []string{}
+		for key := range shard {
+			shardKeys = append(shardKeys, key)
+		}
+		sort.Strings(shardKeys)
+		keys = append(keys, shardKeys)
+	}
+	return keys
+}
+
+var _ = Describe("Output sharding", func() {
+	DescribeTable("planShards",
+		func(sizes map[string]int, previous map[string]int, expected [][]string) {
+			Expect(shardKeys(planShards(sizedEntries(sizes), previous, 100))).To(Equal(expected))
+		},
+		Entry("keeps unchanged placement",
+			map[string]int{"a": 40, "b": 40, "c": 40},
+			map[string]int{"a": 1, "b": 0, "c": 1},
+			[][]string{{"b"}, {"a", "c"}}),
+		Entry("places new keys into the first shard with room",
+			map[string]int{"a": 40, "b": 40, "c": 50, "d": 70},
+			map[string]int{"a": 0, "b": 1},
+			[][]string{{"a", "c"}, {"b"}, {"d"}}),
+		Entry("moves an entry that outgrew its shard",
+			map[string]int{"a": 40, "b": 70},
+			map[string]int{"a": 0, "b": 0},
+			[][]string{{"a"}, {"b"}}),
+		Entry("keeps empty shards in the middle",
+			map[string]int{"a": 40, "c": 40},
+			map[string]int{"a": 0, "b": 1, "c": 2},
+			[][]string{{"a"}, {}, {"c"}}),
+		Entry("drops empty shards at the end",
+			map[string]int{"a": 40},
+			map[string]int{"a": 0, "b": 1, "c": 2},
+			[][]string{{"a"}}),
+		Entry("gives an oversized entry a shard of its own",
+			map[string]int{"a": 40, "big": 150, "c": 40},
+			map[string]int{"a": 0, "big": 0},
+			[][]string{{"a", "c"}, {"big"}}),
+		Entry("places nothing without entries",
+			map[string]int{},
+			map[string]int{"a": 0},
+			[][]string{}),
+	)
+
+	Context("apiOutputEntry", func() {
+		It("leaves out a spec too large for a ConfigMap and reports it", func() {
+			document, err := openapi.Parse([]byte(`{"openapi": "3.0.3", "info": {"title": "Orders", "version": "1.0.0", "description": "` +
+				strings.Repeat("x", maxConfigMapDataBytes) + `"}, "paths": {}}`))
+			Expect(err).NotTo(HaveOccurred())
+			instance := &observabilityv1alpha1.OpenAPIAggregator{}
+			instance.Spec.Output.StoreSpecs = true
+			api := &collectedAPI{Info: observabilityv1alpha1.APIInfo{Name: "orders", Namespace: "team-a"}, Document: document}
+
+			entry, err := apiOutputEntry(instance, api, outputEntry{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.size()).To(BeNumerically("<", 1024))
+			Expect(api.Info.Error).To(ContainSubstring("not stored"))
+
+			stored := decodeConfigMapEntry(entry.Data["team-a.orders"])
+			Expect(stored.Spec).To(BeEmpty())
+			Expect(stored.Error).To(Equal(api.Info.Error))
+		})
+
+		It("stores a spec that fits", func() {
+			document, err := openapi.Parse([]byte(`{"openapi": "3.0.3", "info": {"title": "Orders", "version": "1.0.0"}, "paths": {}}`))
+			Expect(err).NotTo(HaveOccurred())
+			instance := &observabilityv1alpha1.OpenAPIAggregator{}
+			instance.Spec.Output.StoreSpecs = true
+			api := &collectedAPI{Info: observabilityv1alpha1.APIInfo{Name: "orders", Namespace: "team-a"}, Document: document}
+
+			entry, err := apiOutputEntry(instance, api, outputEntry{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.Info.Error).To(BeEmpty())
+			Expect(decodeConfigMapEntry(entry.Data["team-a.orders"]).Spec).NotTo(BeEmpty())
+		})
+	})
+
+	Context("writeOutput", func() {
+		const configMapName = "apis-specs"
+
+		var (
+			r        *OpenAPIAggregatorReconciler
+			instance *observabilityv1alpha1.OpenAPIAggregator
+		)
+
+		shardNames := func() []string {
+			var shards corev1.ConfigMapList
+			Expect(r.List(ctx, &shards, client.InNamespace("team-a"), client.MatchingLabels{shardOfLabel: configMapName})).To(Succeed())
+			names := []string{}
+			for _, shard := range shards.Items {
+				names = append(names, shard.Name)
+			}
+			return names
+		}
+
+		BeforeEach(func() {
+			instance = &observabilityv1alpha1.OpenAPIAggregator{
+				TypeMeta:   metav1.TypeMeta{APIVersion: observabilityv1alpha1.GroupVersion.String(), Kind: "OpenAPIAggregator"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis", UID: types.UID("aggregator-uid")},
+			}
+			// A shard of the same output ConfigMap written by another aggregator is left alone.
+			foreign := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "foreign-0",
+				Labels: map[string]string{aggregatorUIDLabel: "other-uid", shardOfLabel: configMapName}}}
+			r = &OpenAPIAggregatorReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(foreign).Build(),
+				Recorder: record.NewFakeRecorder(100),
+			}
+		})
+
+		It("shards large output, reads it back and deletes shards past the new count", func() {
+			large := 500 * 1024
+			entries := sizedEntries(map[string]int{"team-a.a": large, "team-a.b": large, "team-a.c": large})
+			output, err := r.writeOutput(ctx, instance, configMapName, entries, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Shards).To(Equal([]string{"apis-specs-0", "apis-specs-1", "apis-specs-2"}))
+
+			read, placement, err := r.readOutput(ctx, "team-a", configMapName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read).To(HaveLen(3))
+			Expect(placement).To(Equal(map[string]int{"team-a.a": 0, "team-a.b": 1, "team-a.c": 2}))
+
+			delete(read, "team-a.c")
+			output, err = r.writeOutput(ctx, instance, configMapName, read, placement)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Shards).To(Equal([]string{"apis-specs-0", "apis-specs-1"}))
+			Expect(shardNames()).To(ConsistOf("apis-specs-0", "apis-specs-1", "foreign-0"))
+		})
+
+		It("deletes every shard once the output fits into one ConfigMap", func() {
+			large := 500 * 1024
+			_, err := r.writeOutput(ctx, instance, configMapName, sizedEntries(map[string]int{"team-a.a": large, "team-a.b": large}), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(shardNames()).To(ConsistOf("apis-specs-0", "apis-specs-1", "foreign-0"))
+
+			output, err := r.writeOutput(ctx, instance, configMapName, sizedEntries(map[string]int{"team-a.a": 100}), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Shards).To(BeEmpty())
+			Expect(shardNames()).To(ConsistOf("foreign-0"))
+
+			read, placement, err := r.readOutput(ctx, "team-a", configMapName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read).To(HaveKey("team-a.a"))
+			Expect(placement).To(BeNil())
+		})
+
+		It("labels the shards of a ConfigMap whose name is too long for a label value", func() {
+			longName := strings.Repeat("specs-", 40)[:240]
+			// The fake client does not validate labels, unlike the API server.
+			validLabels := func(obj client.Object) error {
+				for key, value := range obj.GetLabels() {
+					if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+						return fmt.Errorf("invalid value of label %s: %s", key, strings.Join(errs, "; "))
+					}
+				}
+				return nil
+			}
+			r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if err := validLabels(obj); err != nil {
+						return err
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+				Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					if err := validLabels(obj); err != nil {
+						return err
+					}
+					return c.Update(ctx, obj, opts...)
+				},
+			}).Build()
+
+			large := 500 * 1024
+			output, err := r.writeOutput(ctx, instance, longName, sizedEntries(map[string]int{"team-a.a": large, "team-a.b": large}), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Shards).To(HaveLen(2))
+
+			output, err = r.writeOutput(ctx, instance, longName, sizedEntries(map[string]int{"team-a.a": 100}), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Shards).To(BeEmpty())
+			var shards corev1.ConfigMapList
+			Expect(r.List(ctx, &shards, client.InNamespace("team-a"), client.MatchingLabels{shardOfLabel: shardOfLabelValue(longName)})).To(Succeed())
+			Expect(shards.Items).To(BeEmpty())
+		})
+	})
+
+	DescribeTable("shardOfLabelValue",
+		func(configMapName string, unchanged bool) {
+			value := shardOfLabelValue(configMapName)
+			Expect(validation.IsValidLabelValue(value)).To(BeEmpty())
+			if unchanged {
+				Expect(value).To(Equal(configMapName))
+				return
+			}
+			Expect(len(value)).To(BeNumerically("<=", validation.LabelValueMaxLength))
+			Expect(shardOfLabelValue(configMapName + "x")).NotTo(Equal(value))
+		},
+		Entry("a short name", "apis-specs", true),
+		Entry("a name as long as a label value", strings.Repeat("a", validation.LabelValueMaxLength), true),
+		Entry("a name longer than a label value", strings.Repeat("a", 240), false),
+		Entry("a long name cut after a dash", strings.Repeat("a", 45)+strings.Repeat("-", 10)+strings.Repeat("b", 100), false),
+	)
+})
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+const (
+	// defaultResyncInterval is how often specs are fetched again when spec.resyncInterval is not set.
+	defaultResyncInterval = 5 * time.Minute
+
+	// minResyncInterval is the lower bound applied to spec.resyncInterval.
+	minResyncInterval = 10 * time.Second
+
+	// resyncJitterFactor spreads re-fetches of specs over up to this fraction of the resync interval.
+	resyncJitterFactor = 0.2
+
+	// fetchBackoffBase and fetchBackoffMax bound the delay before a failing spec is fetched again.
+	fetchBackoffBase = 10 * time.Second
+	fetchBackoffMax  = 10 * time.Minute
+
+	// minRequeueAfter is the shortest delay before an aggregator is reconciled again for re-fetching.
+	minRequeueAfter = time.Second
+)
+
+// resyncInterval returns the interval after which the specs of an aggregator are fetched again.
+func resyncInterval(instance *observabilityv1alpha1.OpenAPIAggregator) time.Duration {
+	if instance.Spec.ResyncInterval == nil {
+		return defaultResyncInterval
+	}
+	if interval := instance.Spec.ResyncInterval.Duration; interval > minResyncInterval {
+		return interval
+	}
+	return minResyncInterval
+}
+
+// fetchBackoff returns the delay before fetching a spec again after the given number of consecutive failures.
+func fetchBackoff(failures int) time.Duration {
+	delay := fetchBackoffBase
+	for i := 1; i < failures && delay < fetchBackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > fetchBackoffMax {
+		return fetchBackoffMax
+	}
+	return delay
+}
+
+// fetchState is the outcome of the last fetch of an API's spec.
+type fetchState struct {
+	// URL is the address the spec was fetched from; a different URL invalidates the state
+	URL string
+
+	// Result is the fetch result. Its Document is the spec as fetched, before any transform.
+	Result openapi.Result
+
+	// FetchedAt is when the fetch completed
+	FetchedAt time.Time
+
+	// Failures is the number of consecutive failed fetches
+	Failures int
+
+	// NextFetch is when the spec is due to be fetched again
+	NextFetch time.Time
+}
+
+// specCache keeps the last fetch outcome of each API per aggregator, so that reconciles triggered by
+// watch events reuse fetched specs and only APIs that are due are fetched again.
+type specCache struct {
+	mu      sync.Mutex
+	entries map[types.NamespacedName]map[string]*fetchState
+}
+
+// get returns the fetch state of an API if it was fetched from url and is not yet due at now.
+// The returned document is a copy that may be transformed freely.
+func (c *specCache) get(aggregator types.NamespacedName, key, url string, now time.Time) (fetchState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.entries[aggregator][key]
+	if !ok || state.URL != url || !now.Before(state.NextFetch) {
+		return fetchState{}, false
+	}
+	cached := *state
+	if cached.Result.Document != nil {
+		cached.Result.Document = cached.Result.Document.DeepCopy()
+	}
+	return cached, true
+}
+
+// put records the result of fetching an API's spec and schedules its next fetch: after a jittered
+// resync interval on success, or after an exponential backoff on failure.
+func (c *specCache) put(aggregator types.NamespacedName, key, url string, result openapi.Result, now time.Time, resync time.Duration) fetchState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[types.NamespacedName]map[string]*fetchState{}
+	}
+	if c.entries[aggregator] == nil {
+		c.entries[aggregator] = map[string]*fetchState{}
+	}
+
+	state := &fetchState{URL: url, Result: result, FetchedAt: now}
+	if result.Err != nil {
+		if previous, ok := c.entries[aggregator][key]; ok && previous.URL == url {
+			state.Failures = previous.Failures
+		}
+		state.Failures++
+		state.NextFetch = now.Add(fetchBackoff(state.Failures))
+	} else {
+		state.NextFetch = now.Add(wait.Jitter(resync, resyncJitterFactor))
+	}
+	c.entries[aggregator][key] = state
+
+	stored := *state
+	if stored.Result.Document != nil {
+		stored.Result.Document = stored.Result.Document.DeepCopy()
+	}
+	return stored
+}
+
+// retain drops the states of an aggregator's APIs that are not in keys.
+func (c *specCache) retain(aggregator types.NamespacedName, keys map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries[aggregator] {
+		if !keys[key] {
+			delete(c.entries[aggregator], key)
+		}
+	}
+}
+
+// forget drops all states of a deleted aggregator.
+func (c *specCache) forget(aggregator types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, aggregator)
+}
+
+// requeueAfter returns the delay until the next API of an aggregator is due to be fetched,
+// or a jittered resync interval if it has none.
+func (c *specCache) requeueAfter(aggregator types.NamespacedName, now time.Time, resync time.Duration) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	after := wait.Jitter(resync, resyncJitterFactor)
+	for _, state := range c.entries[aggregator] {
+		if due := state.NextFetch.Sub(now); due < after {
+			after = due
+		}
+	}
+	if after < minRequeueAfter {
+		return minRequeueAfter
+	}
+	return after
+}
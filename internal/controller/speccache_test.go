@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/openapi"
+)
+
+var _ = Describe("specCache", func() {
+	const url = "http://orders.team-a.svc.cluster.local:8080/v2/api-docs"
+
+	var (
+		cache      *specCache
+		aggregator = types.NamespacedName{Namespace: "team-a", Name: "apis"}
+		now        time.Time
+		document   *openapi.Document
+		failure    = openapi.Result{Err: errors.New("connection refused"), Reason: openapi.ReasonUnreachable}
+	)
+
+	BeforeEach(func() {
+		cache = &specCache{}
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		var err error
+		document, err = openapi.Parse([]byte(`{"openapi": "3.0.3", "info": {"title": "Orders", "version": "1.0.0"}, "paths": {}}`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("resyncInterval",
+		func(interval *metav1.Duration, expected time.Duration) {
+			instance := &observabilityv1alpha1.OpenAPIAggregator{}
+			instance.Spec.ResyncInterval = interval
+			Expect(resyncInterval(instance)).To(Equal(expected))
+		},
+		Entry("defaults to 5m", nil, defaultResyncInterval),
+		Entry("uses the configured interval", &metav1.Duration{Duration: time.Hour}, time.Hour),
+		Entry("raises a shorter interval to 10s", &metav1.Duration{Duration: time.Second}, minResyncInterval),
+		Entry("raises a zero interval to 10s", &metav1.Duration{}, minResyncInterval),
+	)
+
+	DescribeTable("fetchBackoff",
+		func(failures int, expected time.Duration) {
+			Expect(fetchBackoff(failures)).To(Equal(expected))
+		},
+		Entry("starts at the base delay", 1, 10*time.Second),
+		Entry("doubles on the second failure", 2, 20*time.Second),
+		Entry("doubles on every failure", 5, 160*time.Second),
+		Entry("is capped", 7, fetchBackoffMax),
+		Entry("stays capped", 100, fetchBackoffMax),
+	)
+
+	It("schedules the next fetch of a spec after a jittered resync interval", func() {
+		for i := 0; i < 50; i++ {
+			state := cache.put(aggregator, "team-a.orders", url, openapi.Result{Document: document}, now, time.Minute)
+			Expect(state.Failures).To(BeZero())
+			Expect(state.NextFetch).To(BeTemporally(">=", now.Add(time.Minute)))
+			Expect(state.NextFetch).To(BeTemporally("<=", now.Add(time.Minute+time.Duration(resyncJitterFactor*float64(time.Minute)))))
+		}
+	})
+
+	It("backs off consecutive failures and resets them on success", func() {
+		state := cache.put(aggregator, "team-a.orders", url, failure, now, time.Minute)
+		Expect(state.Failures).To(Equal(1))
+		Expect(state.NextFetch).To(Equal(now.Add(10 * time.Second)))
+
+		state = cache.put(aggregator, "team-a.orders", url, failure, now, time.Minute)
+		Expect(state.Failures).To(Equal(2))
+		Expect(state.NextFetch).To(Equal(now.Add(20 * time.Second)))
+
+		state = cache.put(aggregator, "team-a.orders", url, openapi.Result{Document: document}, now, time.Minute)
+		Expect(state.Failures).To(BeZero())
+
+		state = cache.put(aggregator, "team-a.orders", url, failure, now, time.Minute)
+		Expect(state.Failures).To(Equal(1))
+	})
+
+	It("restarts the backoff when the URL changes", func() {
+		cache.put(aggregator, "team-a.orders", url, failure, now, time.Minute)
+		state := cache.put(aggregator, "team-a.orders", url+"?group=v2", failure, now, time.Minute)
+		Expect(state.Failures).To(Equal(1))
+	})
+
+	It("returns a state until it is due or its URL changes", func() {
+		cache.put(aggregator, "team-a.orders", url, openapi.Result{Document: document}, now, time.Minute)
+
+		state, ok := cache.get(aggregator, "team-a.orders", url, now.Add(30*time.Second))
+		Expect(ok).To(BeTrue())
+		Expect(state.Result.Document.Title).To(Equal("Orders"))
+
+		_, ok = cache.get(aggregator, "team-a.orders", url+"?group=v2", now)
+		Expect(ok).To(BeFalse())
+		_, ok = cache.get(aggregator, "team-a.orders", url, now.Add(2*time.Minute))
+		Expect(ok).To(BeFalse())
+		_, ok = cache.get(aggregator, "team-a.payments", url, now)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("returns copies of the cached document", func() {
+		cache.put(aggregator, "team-a.orders", url, openapi.Result{Document: document}, now, time.Minute)
+
+		state, _ := cache.get(aggregator, "team-a.orders", url, now)
+		state.Result.Document.Title = "changed"
+
+		state, _ = cache.get(aggregator, "team-a.orders", url, now)
+		Expect(state.Result.Document.Title).To(Equal("Orders"))
+	})
+
+	It("requeues when the first API is due", func() {
+		cache.put(aggregator, "team-a.orders", url, openapi.Result{Document: document}, now, time.Hour)
+		cache.put(aggregator, "team-a.payments", url, failure, now, time.Hour)
+
+		Expect(cache.requeueAfter(aggregator, now, time.Hour)).To(Equal(10 * time.Second))
+		Expect(cache.requeueAfter(aggregator, now.Add(5*time.Second), time.Hour)).To(Equal(5 * time.Second))
+	})
+
+	It("requeues after at least a second when an API is overdue", func() {
+		cache.put(aggregator, "team-a.payments", url, failure, now, time.Hour)
+		Expect(cache.requeueAfter(aggregator, now.Add(time.Minute), time.Hour)).To(Equal(minRequeueAfter))
+	})
+
+	It("requeues after a jittered resync interval without APIs", func() {
+		after := cache.requeueAfter(aggregator, now, time.Minute)
+		Expect(after).To(BeNumerically(">=", time.Minute))
+		Expect(after).To(BeNumerically("<=", time.Minute+time.Duration(resyncJitterFactor*float64(time.Minute))))
+	})
+
+	It("drops the states of APIs no longer collected and of deleted aggregators", func() {
+		cache.put(aggregator, "team-a.orders", url, openapi.Result{Document: document}, now, time.Minute)
+		cache.put(aggregator, "team-a.payments", url, openapi.Result{Document: document}, now, time.Minute)
+
+		cache.retain(aggregator, map[string]bool{"team-a.orders": true})
+		_, ok := cache.get(aggregator, "team-a.payments", url, now)
+		Expect(ok).To(BeFalse())
+		_, ok = cache.get(aggregator, "team-a.orders", url, now)
+		Expect(ok).To(BeTrue())
+
+		cache.forget(aggregator)
+		_, ok = cache.get(aggregator, "team-a.orders", url, now)
+		Expect(ok).To(BeFalse())
+	})
+})
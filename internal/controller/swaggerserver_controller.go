@@ -19,6 +19,8 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time" // Added for RequeueAfter
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -30,10 +32,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
 )
@@ -46,6 +51,9 @@ const ConfigMapReadyCondition = "ConfigMapReady"
 type SwaggerServerReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Recorder records events on SwaggerServers. The manager's recorder is used if nil.
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=observability.aggregator.io,resources=swaggerservers,verbs=get;list;watch;create;update;patch;delete
@@ -54,6 +62,7 @@ type SwaggerServerReconciler struct {
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile handles the reconciliation loop for SwaggerServer resources
 func (r *SwaggerServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -80,7 +89,18 @@ func (r *SwaggerServerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	if err := r.ensureDeployment(ctx, instance); err != nil {
+	resources, err := resourceRequirementsToK8s(instance.Spec.Resources)
+	if err != nil {
+		// The spec has to be fixed; it is reconciled again when it changes.
+		logger.Info("Invalid resources in SwaggerServer spec", "error", err.Error())
+		setSwaggerServerDegraded(instance, metav1.ConditionTrue, "InvalidResources", err.Error())
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "InvalidResources", err.Error())
+		instance.Status.Ready = false
+		return ctrl.Result{}, nil
+	}
+	setSwaggerServerDegraded(instance, metav1.ConditionFalse, "SpecValid", "The spec can be applied")
+
+	if err := r.ensureDeployment(ctx, instance, resources); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -110,6 +130,7 @@ func (r *SwaggerServerReconciler) ensureConfigMapReady(ctx context.Context, inst
 			logger.Info("ConfigMap not found", "configmap", instance.Spec.ConfigMapName, "namespace", instance.Namespace)
 			configMapCondition.Reason = "ConfigMapNotFound"
 			configMapCondition.Message = fmt.Sprintf("ConfigMap %s not found in namespace %s", instance.Spec.ConfigMapName, instance.Namespace)
+			r.Recorder.Event(instance, corev1.EventTypeWarning, configMapCondition.Reason, configMapCondition.Message)
 		} else {
 			logger.Error(err, "Failed to get ConfigMap", "configmap", instance.Spec.ConfigMapName)
 			configMapCondition.Reason = "GetConfigMapFailed"
@@ -127,13 +148,30 @@ func (r *SwaggerServerReconciler) ensureConfigMapReady(ctx context.Context, inst
 	return nil
 }
 
-func (r *SwaggerServerReconciler) ensureDeployment(ctx context.Context, instance *observabilityv1alpha1.SwaggerServer) error {
+func (r *SwaggerServerReconciler) ensureDeployment(ctx context.Context, instance *observabilityv1alpha1.SwaggerServer, resources corev1.ResourceRequirements) error {
 	logger := log.FromContext(ctx)
 	image := instance.Spec.Image
 	if image == "" {
 		image = "ghcr.io/hellices/openapi-multi-swagger:latest"
 	}
 
+	env := []corev1.EnvVar{
+		{Name: "CONFIGMAP_NAME", Value: instance.Spec.ConfigMapName},
+		{Name: "NAMESPACE", Value: instance.Namespace},
+		{Name: "PORT", Value: fmt.Sprintf("%d", instance.Spec.Port)},
+		{Name: "WATCH_INTERVAL_SECONDS", Value: getValueOrDefault(instance.Spec.WatchIntervalSeconds, "10")},
+		{Name: "LOG_LEVEL", Value: getValueOrDefault(instance.Spec.LogLevel, "info")},
+		{Name: "DEV_MODE", Value: getValueOrDefault(instance.Spec.DevMode, "false")},
+	}
+	shards, err := r.configMapShards(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if len(shards) > 0 {
+		// The ConfigMap only holds an index; the UI loads the entries from the shards.
+		env = append(env, corev1.EnvVar{Name: "CONFIGMAP_SHARDS", Value: strings.Join(shards, ",")})
+	}
+
 	deploy := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instance.Name,
@@ -141,7 +179,7 @@ func (r *SwaggerServerReconciler) ensureDeployment(ctx context.Context, instance
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
 		deploy.Spec = appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{"app": instance.Name},
@@ -159,18 +197,8 @@ func (r *SwaggerServerReconciler) ensureDeployment(ctx context.Context, instance
 							Ports: []corev1.ContainerPort{
 								{ContainerPort: instance.Spec.Port, Protocol: corev1.ProtocolTCP},
 							},
-							Env: []corev1.EnvVar{
-								{Name: "CONFIGMAP_NAME", Value: instance.Spec.ConfigMapName},
-								{Name: "NAMESPACE", Value: instance.Namespace},
-								{Name: "PORT", Value: fmt.Sprintf("%d", instance.Spec.Port)},
-								{Name: "WATCH_INTERVAL_SECONDS", Value: getValueOrDefault(instance.Spec.WatchIntervalSeconds, "10")},
-								{Name: "LOG_LEVEL", Value: getValueOrDefault(instance.Spec.LogLevel, "info")},
-								{Name: "DEV_MODE", Value: getValueOrDefault(instance.Spec.DevMode, "false")},
-							},
-							Resources: corev1.ResourceRequirements{
-								Limits:   resourceListToK8s(instance.Spec.Resources.Limits),
-								Requests: resourceListToK8s(instance.Spec.Resources.Requests),
-							},
+							Env:       env,
+							Resources: resources,
 						},
 					},
 				},
@@ -181,8 +209,12 @@ func (r *SwaggerServerReconciler) ensureDeployment(ctx context.Context, instance
 
 	if err != nil {
 		logger.Error(err, "Failed to ensure Deployment")
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "DeploymentFailed", "Failed to ensure Deployment %s: %v", deploy.Name, err)
 		return err
 	}
+	if result == controllerutil.OperationResultCreated {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "DeploymentCreated", "Created Deployment %s", deploy.Name)
+	}
 	return nil
 }
 
@@ -195,7 +227,7 @@ func (r *SwaggerServerReconciler) ensureService(ctx context.Context, instance *o
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
 		svc.Spec = corev1.ServiceSpec{
 			Selector: map[string]string{"app": instance.Name},
 			Ports: []corev1.ServicePort{
@@ -212,11 +244,55 @@ func (r *SwaggerServerReconciler) ensureService(ctx context.Context, instance *o
 
 	if err != nil {
 		logger.Error(err, "Failed to ensure Service")
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "ServiceFailed", "Failed to ensure Service %s: %v", svc.Name, err)
 		return err
 	}
+	if result == controllerutil.OperationResultCreated {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ServiceCreated", "Created Service %s", svc.Name)
+	}
 	return nil
 }
 
+// configMapShards returns the shards listed in the status of the OpenAPIAggregator that writes the
+// SwaggerServer's ConfigMap, or nil if the output is not sharded.
+func (r *SwaggerServerReconciler) configMapShards(ctx context.Context, instance *observabilityv1alpha1.SwaggerServer) ([]string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.ConfigMapName, Namespace: instance.Namespace}, cm); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	owner := metav1.GetControllerOf(cm)
+	if owner == nil || owner.Kind != "OpenAPIAggregator" {
+		return nil, nil
+	}
+
+	aggregator := &observabilityv1alpha1.OpenAPIAggregator{}
+	if err := r.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: instance.Namespace}, aggregator); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return aggregator.Status.Output.Shards, nil
+}
+
+// swaggerServersForAggregator maps an OpenAPIAggregator to the SwaggerServers serving its output ConfigMap.
+func (r *SwaggerServerReconciler) swaggerServersForAggregator(ctx context.Context, obj client.Object) []reconcile.Request {
+	aggregator, ok := obj.(*observabilityv1alpha1.OpenAPIAggregator)
+	if !ok || aggregator.Status.Output.ConfigMapName == "" {
+		return nil
+	}
+
+	var servers observabilityv1alpha1.SwaggerServerList
+	if err := r.List(ctx, &servers, client.InNamespace(aggregator.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list SwaggerServers for OpenAPIAggregator", "aggregator", aggregator.Name)
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, server := range servers.Items {
+		if server.Spec.ConfigMapName == aggregator.Status.Output.ConfigMapName {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: server.Namespace, Name: server.Name}})
+		}
+	}
+	return requests
+}
+
 // getValueOrDefault returns the value if not empty, otherwise returns the default value.
 func getValueOrDefault(value, defaultValue string) string {
 	if value != "" {
@@ -227,18 +303,60 @@ func getValueOrDefault(value, defaultValue string) string {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SwaggerServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("swaggerserver-controller")
+	}
+	r.Recorder = newThrottledRecorder(r.Recorder, eventRepeatInterval)
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&observabilityv1alpha1.SwaggerServer{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		// Shards of the output ConfigMap are published in the aggregator's status.
+		Watches(&observabilityv1alpha1.OpenAPIAggregator{}, handler.EnqueueRequestsFromMapFunc(r.swaggerServersForAggregator)).
 		Complete(r)
 }
 
+// setSwaggerServerDegraded sets the Degraded condition of a SwaggerServer.
+func setSwaggerServerDegraded(instance *observabilityv1alpha1.SwaggerServer, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               DegradedCondition,
+		Status:             status,
+		ObservedGeneration: instance.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// resourceRequirementsToK8s converts our ResourceRequirements to k8s ResourceRequirements.
+// It returns an error naming the first quantity that does not parse.
+func resourceRequirementsToK8s(rr observabilityv1alpha1.ResourceRequirements) (corev1.ResourceRequirements, error) {
+	limits, err := resourceListToK8s(rr.Limits)
+	if err != nil {
+		return corev1.ResourceRequirements{}, fmt.Errorf("resources.limits: %w", err)
+	}
+	requests, err := resourceListToK8s(rr.Requests)
+	if err != nil {
+		return corev1.ResourceRequirements{}, fmt.Errorf("resources.requests: %w", err)
+	}
+	return corev1.ResourceRequirements{Limits: limits, Requests: requests}, nil
+}
+
 // resourceListToK8s converts our ResourceList to k8s ResourceList
-func resourceListToK8s(rl observabilityv1alpha1.ResourceList) corev1.ResourceList {
+func resourceListToK8s(rl observabilityv1alpha1.ResourceList) (corev1.ResourceList, error) {
+	names := make([]string, 0, len(rl))
+	for k := range rl {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
 	result := corev1.ResourceList{}
-	for k, v := range rl {
-		result[corev1.ResourceName(k)] = resource.MustParse(v)
+	for _, k := range names {
+		quantity, err := resource.ParseQuantity(rl[k])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q for %s: %w", rl[k], k, err)
+		}
+		result[corev1.ResourceName(k)] = quantity
 	}
-	return result
+	return result, nil
 }
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+)
+
+var _ = Describe("SwaggerServer Controller", func() {
+	DescribeTable("resourceRequirementsToK8s",
+		func(rr observabilityv1alpha1.ResourceRequirements, expectedErr string) {
+			resources, err := resourceRequirementsToK8s(rr)
+			if expectedErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			for name, value := range rr.Limits {
+				Expect(resources.Limits[corev1.ResourceName(name)]).To(Equal(resource.MustParse(value)))
+			}
+			for name, value := range rr.Requests {
+				Expect(resources.Requests[corev1.ResourceName(name)]).To(Equal(resource.MustParse(value)))
+			}
+		},
+		Entry("valid quantities", observabilityv1alpha1.ResourceRequirements{
+			Limits:   observabilityv1alpha1.ResourceList{"cpu": "500m", "memory": "128Mi"},
+			Requests: observabilityv1alpha1.ResourceList{"cpu": "100m"},
+		}, ""),
+		Entry("no quantities", observabilityv1alpha1.ResourceRequirements{}, ""),
+		Entry("an unparsable limit", observabilityv1alpha1.ResourceRequirements{
+			Limits: observabilityv1alpha1.ResourceList{"cpu": "500m", "memory": "lots"},
+		}, `resources.limits: invalid quantity "lots" for memory`),
+		Entry("an unparsable request", observabilityv1alpha1.ResourceRequirements{
+			Requests: observabilityv1alpha1.ResourceList{"cpu": "1 core"},
+		}, `resources.requests: invalid quantity "1 core" for cpu`),
+	)
+
+	Describe("Reconcile", func() {
+		var (
+			r        *SwaggerServerReconciler
+			recorder *record.FakeRecorder
+			instance *observabilityv1alpha1.SwaggerServer
+		)
+
+		BeforeEach(func() {
+			instance = &observabilityv1alpha1.SwaggerServer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "swagger", Generation: 2},
+				Spec: observabilityv1alpha1.SwaggerServerSpec{
+					ConfigMapName: "apis-specs",
+					Port:          9090,
+					Resources: observabilityv1alpha1.ResourceRequirements{
+						Limits: observabilityv1alpha1.ResourceList{"memory": "lots"},
+					},
+				},
+			}
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "apis-specs"}}
+			recorder = record.NewFakeRecorder(10)
+			r = &SwaggerServerReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).
+					WithObjects(instance, configMap).WithStatusSubresource(instance).Build(),
+				Scheme:   scheme.Scheme,
+				Recorder: recorder,
+			}
+		})
+
+		It("sets Degraded instead of failing when a quantity does not parse", func() {
+			request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "swagger"}}
+			Expect(func() {
+				result, err := r.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+			}).NotTo(Panic())
+
+			updated := &observabilityv1alpha1.SwaggerServer{}
+			Expect(r.Get(ctx, request.NamespacedName, updated)).To(Succeed())
+			condition := apimeta.FindStatusCondition(updated.Status.Conditions, DegradedCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("InvalidResources"))
+			Expect(condition.Message).To(ContainSubstring(`invalid quantity "lots" for memory`))
+			Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+			Expect(updated.Status.Ready).To(BeFalse())
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidResources")))
+
+			deployment := &appsv1.Deployment{}
+			err := r.Get(ctx, request.NamespacedName, deployment)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oci pushes artifacts to registries implementing the OCI distribution API.
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+const (
+	// MediaTypeImageManifest is the media type of an OCI image manifest.
+	MediaTypeImageManifest = "application/vnd.oci.image.manifest.v1+json"
+
+	// MediaTypeImageIndex is the media type of an OCI image index.
+	MediaTypeImageIndex = "application/vnd.oci.image.index.v1+json"
+
+	// MediaTypeEmptyJSON is the media type of the empty config of artifacts that have no config.
+	MediaTypeEmptyJSON = "application/vnd.oci.empty.v1+json"
+
+	// AnnotationTitle is the annotation holding the file name of a layer.
+	AnnotationTitle = "org.opencontainers.image.title"
+)
+
+// emptyJSON is the content of the empty config blob.
+var emptyJSON = []byte("{}")
+
+// Descriptor describes a blob or manifest by media type, digest and size.
+type Descriptor struct {
+	MediaType    string            `json:"mediaType"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an OCI image manifest.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Index is an OCI image index.
+type Index struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Manifests     []Descriptor      `json:"manifests"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Layer is a file stored in an artifact.
+type Layer struct {
+	// MediaType is the media type of the content
+	MediaType string
+
+	// Title is the file name of the layer
+	Title string
+
+	// Content is the content of the layer
+	Content []byte
+}
+
+// Artifact is a set of layers pushed under one manifest.
+type Artifact struct {
+	// ArtifactType is the type of the artifact recorded in its manifest
+	ArtifactType string
+
+	// Layers are the files of the artifact, in order
+	Layers []Layer
+
+	// Annotations are recorded in the manifest
+	Annotations map[string]string
+}
+
+// Digest returns the digest of content, as "sha256:<hex>".
+func Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Manifest returns the manifest of the artifact and its encoding. The encoding only depends on the
+// content of the artifact, so an unchanged artifact always has the same manifest digest.
+func (a Artifact) Manifest() (Manifest, []byte, error) {
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		ArtifactType:  a.ArtifactType,
+		Config: Descriptor{
+			MediaType: MediaTypeEmptyJSON,
+			Digest:    Digest(emptyJSON),
+			Size:      int64(len(emptyJSON)),
+		},
+		Layers:      make([]Descriptor, 0, len(a.Layers)),
+		Annotations: a.Annotations,
+	}
+	for _, layer := range a.Layers {
+		manifest.Layers = append(manifest.Layers, Descriptor{
+			MediaType:   layer.MediaType,
+			Digest:      Digest(layer.Content),
+			Size:        int64(len(layer.Content)),
+			Annotations: map[string]string{AnnotationTitle: layer.Title},
+		})
+	}
+	data, err := json.Marshal(manifest)
+	return manifest, data, err
+}
+
+// ArtifactIndex is a set of artifacts pushed under one image index.
+type ArtifactIndex struct {
+	// ArtifactType is the type recorded in the index
+	ArtifactType string
+
+	// Artifacts are the artifacts referenced by the index, in order. The annotations of each artifact
+	// are also recorded on its descriptor in the index.
+	Artifacts []Artifact
+
+	// Annotations are recorded in the index
+	Annotations map[string]string
+}
+
+// Index returns the image index of the artifacts and its encoding. Like a manifest, the encoding only
+// depends on the content of the artifacts.
+func (a ArtifactIndex) Index() (Index, []byte, error) {
+	index := Index{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageIndex,
+		ArtifactType:  a.ArtifactType,
+		Manifests:     make([]Descriptor, 0, len(a.Artifacts)),
+		Annotations:   a.Annotations,
+	}
+	for _, artifact := range a.Artifacts {
+		_, manifestJSON, err := artifact.Manifest()
+		if err != nil {
+			return index, nil, err
+		}
+		index.Manifests = append(index.Manifests, Descriptor{
+			MediaType:    MediaTypeImageManifest,
+			ArtifactType: artifact.ArtifactType,
+			Digest:       Digest(manifestJSON),
+			Size:         int64(len(manifestJSON)),
+			Annotations:  artifact.Annotations,
+		})
+	}
+	data, err := json.Marshal(index)
+	return index, data, err
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a whole push when the client has no HTTP client of its own.
+	DefaultTimeout = 2 * time.Minute
+
+	// maxErrorBodyBytes bounds how much of an error response is included in the returned error.
+	maxErrorBodyBytes = 4 * 1024
+)
+
+var (
+	// repositoryPattern matches the path of a repository in a registry.
+	repositoryPattern = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+
+	// tagPattern matches a valid tag.
+	tagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._-]{0,127}$`)
+
+	// challengeParamPattern matches a parameter of a WWW-Authenticate challenge.
+	challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// Reference names a tag of a repository in a registry.
+type Reference struct {
+	// Registry is the host[:port] of the registry
+	Registry string
+
+	// Repository is the path of the repository in the registry
+	Repository string
+
+	// Tag is the tag the manifest is pushed under
+	Tag string
+}
+
+// String returns the reference as "<registry>/<repository>:<tag>".
+func (r Reference) String() string {
+	return r.Registry + "/" + r.Repository + ":" + r.Tag
+}
+
+// ParseRepository splits "<registry>/<repository>" into the registry host and the repository path.
+func ParseRepository(repository string) (string, string, error) {
+	registry, path, ok := strings.Cut(repository, "/")
+	if !ok || registry == "" {
+		return "", "", fmt.Errorf("repository %q must be given as <registry>/<path>", repository)
+	}
+	if !repositoryPattern.MatchString(path) {
+		return "", "", fmt.Errorf("invalid repository path %q", path)
+	}
+	return registry, path, nil
+}
+
+// ValidTag reports whether tag can be used as a tag.
+func ValidTag(tag string) bool {
+	return tagPattern.MatchString(tag)
+}
+
+// Credentials authenticate to a registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Client pushes artifacts to a registry.
+type Client struct {
+	// HTTPClient sends the requests. A client with DefaultTimeout is used if nil.
+	HTTPClient *http.Client
+
+	// Credentials, if set, are used when the registry asks for authentication
+	Credentials *Credentials
+
+	// PlainHTTP connects to the registry over HTTP instead of HTTPS
+	PlainHTTP bool
+}
+
+// Push uploads the blobs of an artifact that the registry does not have yet, then tags its manifest,
+// and returns the digest of the manifest.
+func (c *Client) Push(ctx context.Context, ref Reference, artifact Artifact) (string, error) {
+	if !ValidTag(ref.Tag) {
+		return "", fmt.Errorf("invalid tag %q", ref.Tag)
+	}
+	_, manifestJSON, err := artifact.Manifest()
+	if err != nil {
+		return "", err
+	}
+
+	s := c.session(ref)
+	if err := s.pushBlobs(ctx, artifact); err != nil {
+		return "", err
+	}
+	if err := s.pushManifest(ctx, ref.Tag, MediaTypeImageManifest, manifestJSON); err != nil {
+		return "", err
+	}
+	return Digest(manifestJSON), nil
+}
+
+// PushIndex uploads the blobs of the artifacts of an index that the registry does not have yet, pushes
+// their manifests by digest, then tags the index, and returns the digest of the index.
+func (c *Client) PushIndex(ctx context.Context, ref Reference, index ArtifactIndex) (string, error) {
+	if !ValidTag(ref.Tag) {
+		return "", fmt.Errorf("invalid tag %q", ref.Tag)
+	}
+	_, indexJSON, err := index.Index()
+	if err != nil {
+		return "", err
+	}
+
+	s := c.session(ref)
+	if err := s.pushBlobs(ctx, index.Artifacts...); err != nil {
+		return "", err
+	}
+	for _, artifact := range index.Artifacts {
+		_, manifestJSON, err := artifact.Manifest()
+		if err != nil {
+			return "", err
+		}
+		if err := s.pushManifest(ctx, Digest(manifestJSON), MediaTypeImageManifest, manifestJSON); err != nil {
+			return "", err
+		}
+	}
+	if err := s.pushManifest(ctx, ref.Tag, MediaTypeImageIndex, indexJSON); err != nil {
+		return "", err
+	}
+	return Digest(indexJSON), nil
+}
+
+// session starts a push to the repository of ref.
+func (c *Client) session(ref Reference) *session {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	scheme := "https"
+	if c.PlainHTTP {
+		scheme = "http"
+	}
+	return &session{
+		httpClient:  httpClient,
+		credentials: c.Credentials,
+		base:        &url.URL{Scheme: scheme, Host: ref.Registry},
+		repository:  ref.Repository,
+	}
+}
+
+// session holds the state of one push, in particular the authorization obtained from the registry.
+type session struct {
+	httpClient    *http.Client
+	credentials   *Credentials
+	base          *url.URL
+	repository    string
+	authorization string
+}
+
+// url returns the URL of a path below the repository.
+func (s *session) url(path string) *url.URL {
+	return s.base.ResolveReference(&url.URL{Path: "/v2/" + s.repository + "/" + path})
+}
+
+// pushBlobs uploads the config and layer blobs of artifacts that the registry does not have yet.
+func (s *session) pushBlobs(ctx context.Context, artifacts ...Artifact) error {
+	blobs := [][]byte{emptyJSON}
+	for _, artifact := range artifacts {
+		for _, layer := range artifact.Layers {
+			blobs = append(blobs, layer.Content)
+		}
+	}
+	pushed := map[string]bool{}
+	for _, blob := range blobs {
+		digest := Digest(blob)
+		if pushed[digest] {
+			continue
+		}
+		if err := s.pushBlob(ctx, digest, blob); err != nil {
+			return fmt.Errorf("pushing blob %s: %w", digest, err)
+		}
+		pushed[digest] = true
+	}
+	return nil
+}
+
+// pushManifest pushes a manifest or an index under a tag or its digest.
+func (s *session) pushManifest(ctx context.Context, reference, mediaType string, content []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.url("manifests/"+reference), mediaType, content)
+	if err != nil {
+		return fmt.Errorf("pushing manifest %s: %w", reference, err)
+	}
+	defer closeBody(resp)
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("pushing manifest %s: %w", reference, statusError(resp))
+	}
+	return nil
+}
+
+// pushBlob uploads a blob in a single request, unless the registry already has it.
+func (s *session) pushBlob(ctx context.Context, digest string, content []byte) error {
+	resp, err := s.do(ctx, http.MethodHead, s.url("blobs/"+digest), "", nil)
+	if err != nil {
+		return err
+	}
+	closeBody(resp)
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = s.do(ctx, http.MethodPost, s.url("blobs/uploads/"), "", nil)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	if resp.StatusCode != http.StatusAccepted {
+		return statusError(resp)
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("upload location: %w", err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = s.do(ctx, http.MethodPut, location, "application/octet-stream", content)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	if resp.StatusCode != http.StatusCreated {
+		return statusError(resp)
+	}
+	return nil
+}
+
+// do sends a request, authenticating and sending it again once if the registry asks for it.
+func (s *session) do(ctx context.Context, method string, target *url.URL, contentType string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if s.authorization != "" {
+			req.Header.Set("Authorization", s.authorization)
+		}
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+		challenge := resp.Header.Get("WWW-Authenticate")
+		closeBody(resp)
+		if err := s.authorize(ctx, challenge); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// authorize answers an authentication challenge of the registry, with basic authentication
+// or with a token obtained from the registry's token service.
+func (s *session) authorize(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if s.credentials == nil {
+			return fmt.Errorf("registry %s requires credentials", s.base.Host)
+		}
+		s.authorization = "Basic " + basicAuth(s.credentials)
+		return nil
+	case "bearer":
+		token, err := s.fetchToken(ctx, challengeParams(params))
+		if err != nil {
+			return fmt.Errorf("fetching registry token: %w", err)
+		}
+		s.authorization = "Bearer " + token
+		return nil
+	default:
+		return fmt.Errorf("registry %s requires unsupported authentication %q", s.base.Host, challenge)
+	}
+}
+
+// fetchToken requests a token for pushing to the repository from the token service named in a challenge.
+func (s *session) fetchToken(ctx context.Context, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid realm %q", params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", "repository:"+s.repository+":pull,push")
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if s.credentials != nil {
+		req.Header.Set("Authorization", "Basic "+basicAuth(s.credentials))
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer closeBody(resp)
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("token service returned no token")
+}
+
+// challengeParams parses the parameters of a WWW-Authenticate challenge.
+func challengeParams(params string) map[string]string {
+	parsed := map[string]string{}
+	for _, match := range challengeParamPattern.FindAllStringSubmatch(params, -1) {
+		parsed[strings.ToLower(match[1])] = match[2]
+	}
+	return parsed
+}
+
+// basicAuth returns the basic authentication credentials.
+func basicAuth(credentials *Credentials) string {
+	return base64.StdEncoding.EncodeToString([]byte(credentials.Username + ":" + credentials.Password))
+}
+
+// statusError describes an unexpected response, including the start of its body.
+func statusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if len(body) == 0 {
+		return fmt.Errorf("%s %s: unexpected status %s", resp.Request.Method, resp.Request.URL.Path, resp.Status)
+	}
+	return fmt.Errorf("%s %s: unexpected status %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+}
+
+// closeBody drains and closes a response body, so the connection can be reused.
+func closeBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+	_ = resp.Body.Close()
+}
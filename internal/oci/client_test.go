@@ -0,0 +1,187 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		registry *testRegistry
+		server   *httptest.Server
+		ref      Reference
+		artifact Artifact
+	)
+
+	BeforeEach(func() {
+		registry = newTestRegistry()
+		server = httptest.NewServer(registry)
+		DeferCleanup(server.Close)
+
+		serverURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		ref = Reference{Registry: serverURL.Host, Repository: "platform/apis", Tag: "v1"}
+		artifact = Artifact{
+			ArtifactType: "application/vnd.example.catalog.v1",
+			Layers: []Layer{
+				{MediaType: "application/json", Title: "a.json", Content: []byte(`{"a":1}`)},
+				{MediaType: "application/json", Title: "b.json", Content: []byte(`{"b":2}`)},
+			},
+		}
+	})
+
+	It("pushes the blobs and the manifest of an artifact", func() {
+		client := &Client{PlainHTTP: true}
+		digest, err := client.Push(context.Background(), ref, artifact)
+		Expect(err).NotTo(HaveOccurred())
+
+		manifestJSON := registry.manifests["platform/apis/manifests/v1"]
+		Expect(manifestJSON).NotTo(BeNil())
+		Expect(digest).To(Equal(Digest(manifestJSON)))
+
+		var manifest Manifest
+		Expect(json.Unmarshal(manifestJSON, &manifest)).To(Succeed())
+		Expect(manifest.ArtifactType).To(Equal("application/vnd.example.catalog.v1"))
+		Expect(manifest.Config.MediaType).To(Equal(MediaTypeEmptyJSON))
+		Expect(manifest.Layers).To(HaveLen(2))
+		Expect(manifest.Layers[1].Annotations).To(HaveKeyWithValue(AnnotationTitle, "b.json"))
+		Expect(registry.blobs).To(HaveKeyWithValue(manifest.Layers[0].Digest, []byte(`{"a":1}`)))
+		Expect(registry.blobs).To(HaveKey(manifest.Config.Digest))
+	})
+
+	It("only uploads blobs the registry does not have", func() {
+		client := &Client{PlainHTTP: true}
+		first, err := client.Push(context.Background(), ref, artifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(registry.uploads).To(Equal(3))
+
+		artifact.Layers[1].Content = []byte(`{"b":3}`)
+		ref.Tag = "v2"
+		second, err := client.Push(context.Background(), ref, artifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(registry.uploads).To(Equal(4))
+		Expect(second).NotTo(Equal(first))
+	})
+
+	It("pushes the manifests of an index by digest and tags the index", func() {
+		other := Artifact{
+			ArtifactType: "application/vnd.example.spec.v1",
+			Layers:       []Layer{{MediaType: "application/json", Title: "a.json", Content: []byte(`{"a":1}`)}},
+			Annotations:  map[string]string{AnnotationTitle: "a"},
+		}
+		index := ArtifactIndex{
+			ArtifactType: "application/vnd.example.catalog.v1",
+			Artifacts:    []Artifact{artifact, other},
+			Annotations:  map[string]string{"example.com/owner": "platform"},
+		}
+		digest, err := (&Client{PlainHTTP: true}).PushIndex(context.Background(), ref, index)
+		Expect(err).NotTo(HaveOccurred())
+		// The layer shared by both artifacts and the empty config are uploaded once.
+		Expect(registry.uploads).To(Equal(3))
+
+		indexJSON := registry.manifests["platform/apis/manifests/v1"]
+		Expect(indexJSON).NotTo(BeNil())
+		Expect(digest).To(Equal(Digest(indexJSON)))
+
+		var pushed Index
+		Expect(json.Unmarshal(indexJSON, &pushed)).To(Succeed())
+		Expect(pushed.MediaType).To(Equal(MediaTypeImageIndex))
+		Expect(pushed.ArtifactType).To(Equal("application/vnd.example.catalog.v1"))
+		Expect(pushed.Annotations).To(HaveKeyWithValue("example.com/owner", "platform"))
+		Expect(pushed.Manifests).To(HaveLen(2))
+		Expect(pushed.Manifests[1].ArtifactType).To(Equal("application/vnd.example.spec.v1"))
+		Expect(pushed.Manifests[1].Annotations).To(HaveKeyWithValue(AnnotationTitle, "a"))
+		for _, manifest := range pushed.Manifests {
+			Expect(manifest.MediaType).To(Equal(MediaTypeImageManifest))
+			Expect(registry.manifests).To(HaveKey("platform/apis/manifests/" + manifest.Digest))
+			Expect(Digest(registry.manifests["platform/apis/manifests/"+manifest.Digest])).To(Equal(manifest.Digest))
+		}
+	})
+
+	It("produces the same manifest digest for the same content", func() {
+		_, first, err := artifact.Manifest()
+		Expect(err).NotTo(HaveOccurred())
+		_, second, err := artifact.Manifest()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Digest(first)).To(Equal(Digest(second)))
+	})
+
+	It("authenticates with the credentials when the registry asks for them", func() {
+		registry.username, registry.password = "robot", "secret"
+
+		_, err := (&Client{PlainHTTP: true}).Push(context.Background(), ref, artifact)
+		Expect(err).To(MatchError(ContainSubstring("requires credentials")))
+
+		_, err = (&Client{PlainHTTP: true, Credentials: &Credentials{Username: "robot", Password: "wrong"}}).Push(context.Background(), ref, artifact)
+		Expect(err).To(MatchError(ContainSubstring("401")))
+
+		_, err = (&Client{PlainHTTP: true, Credentials: &Credentials{Username: "robot", Password: "secret"}}).Push(context.Background(), ref, artifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(registry.manifests).To(HaveKey("platform/apis/manifests/v1"))
+	})
+
+	It("obtains a token from the token service of the registry", func() {
+		var scope string
+		tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope = r.URL.Query().Get("scope")
+			_, _ = w.Write([]byte(`{"token":"t0ken"}`))
+		}))
+		DeferCleanup(tokens.Close)
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer t0ken" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+tokens.URL+`/token",service="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			registry.ServeHTTP(w, r)
+		})
+
+		_, err := (&Client{PlainHTTP: true}).Push(context.Background(), ref, artifact)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scope).To(Equal("repository:platform/apis:pull,push"))
+	})
+
+	It("rejects invalid tags", func() {
+		ref.Tag = "-bad"
+		_, err := (&Client{PlainHTTP: true}).Push(context.Background(), ref, artifact)
+		Expect(err).To(MatchError(ContainSubstring("invalid tag")))
+	})
+})
+
+var _ = Describe("ParseRepository", func() {
+	It("splits the registry from the repository path", func() {
+		registry, repository, err := ParseRepository("registry.example.com:5000/platform/api-catalog")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(registry).To(Equal("registry.example.com:5000"))
+		Expect(repository).To(Equal("platform/api-catalog"))
+	})
+
+	It("rejects repositories without a registry or with an invalid path", func() {
+		_, _, err := ParseRepository("api-catalog")
+		Expect(err).To(HaveOccurred())
+		_, _, err = ParseRepository("registry.example.com/Platform")
+		Expect(err).To(HaveOccurred())
+	})
+})
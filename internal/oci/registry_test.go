@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// testRegistry is an in-process registry implementing the parts of the distribution API used to push.
+type testRegistry struct {
+	// username and password, if set, are required through basic authentication
+	username string
+	password string
+
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+}
+
+func newTestRegistry() *testRegistry {
+	return &testRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+}
+
+func (t *testRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if t.username != "" {
+		if username, password, ok := r.BasicAuth(); !ok || username != t.username || password != t.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case r.Method == http.MethodHead && strings.Contains(path, "/blobs/"):
+		if _, ok := t.blobs[path[strings.LastIndex(path, "/")+1:]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/blobs/uploads/"):
+		w.Header().Set("Location", r.URL.Path+"session?state=abc")
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPut && strings.Contains(path, "/blobs/uploads/"):
+		body, _ := io.ReadAll(r.Body)
+		digest := r.URL.Query().Get("digest")
+		if r.URL.Query().Get("state") != "abc" || Digest(body) != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		t.blobs[digest] = body
+		t.uploads++
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && strings.Contains(path, "/manifests/"):
+		body, _ := io.ReadAll(r.Body)
+		if contentType := r.Header.Get("Content-Type"); contentType != MediaTypeImageManifest && contentType != MediaTypeImageIndex {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		t.manifests[path] = body
+		w.Header().Set("Docker-Content-Digest", Digest(body))
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
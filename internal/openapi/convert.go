@@ -0,0 +1,584 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConvertedSpecVersion is the OpenAPI version of documents produced by ConvertSwagger2
+const ConvertedSpecVersion = "3.0.3"
+
+// parameterSchemaFields are the Swagger 2.0 parameter fields that move into the parameter schema in OpenAPI 3.
+var parameterSchemaFields = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+// ConvertSwagger2 converts a Swagger 2.0 document to OpenAPI 3.0. The source document is not modified.
+// Constructs without an OpenAPI 3 equivalent are dropped or approximated and reported as warnings.
+func ConvertSwagger2(doc *Document) (*Document, []string, error) {
+	if !doc.IsSwagger2() {
+		return nil, nil, fmt.Errorf("document is not a Swagger 2.0 document (version %s)", doc.SpecVersion)
+	}
+
+	src := deepCopyJSON(doc.Content).(map[string]interface{})
+	c := &converter{
+		src:              src,
+		globalParameters: objectField(src, "parameters"),
+		consumes:         stringList(src["consumes"], "application/json"),
+		produces:         stringList(src["produces"], "application/json"),
+	}
+	out := c.convert()
+	rewriteSwagger2Refs(out, c.globalParameters)
+
+	converted := &Document{
+		Content:     out,
+		SpecVersion: ConvertedSpecVersion,
+		Title:       doc.Title,
+		Version:     doc.Version,
+	}
+	return converted, c.sortedWarnings(), nil
+}
+
+type converter struct {
+	src              map[string]interface{}
+	globalParameters map[string]interface{}
+	consumes         []string
+	produces         []string
+	warnings         []string
+}
+
+func (c *converter) warn(format string, args ...interface{}) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+// sortedWarnings returns the distinct warnings in a stable order, since they are produced while iterating maps.
+func (c *converter) sortedWarnings() []string {
+	sort.Strings(c.warnings)
+	var out []string
+	for i, warning := range c.warnings {
+		if i == 0 || warning != c.warnings[i-1] {
+			out = append(out, warning)
+		}
+	}
+	return out
+}
+
+func (c *converter) convert() map[string]interface{} {
+	out := map[string]interface{}{
+		"openapi": ConvertedSpecVersion,
+		"info":    c.src["info"],
+		"servers": c.servers(),
+	}
+	for key, value := range c.src {
+		if key == "tags" || key == "security" || key == "externalDocs" || strings.HasPrefix(key, "x-") {
+			out[key] = value
+		}
+	}
+
+	components := map[string]interface{}{}
+	if definitions := objectField(c.src, "definitions"); len(definitions) > 0 {
+		for name, schema := range definitions {
+			convertSchema(schema)
+			definitions[name] = schema
+		}
+		components["schemas"] = definitions
+	}
+	if schemes := c.securitySchemes(); len(schemes) > 0 {
+		components["securitySchemes"] = schemes
+	}
+	c.convertGlobalParameters(components)
+	if responses := objectField(c.src, "responses"); len(responses) > 0 {
+		converted := map[string]interface{}{}
+		for name, response := range responses {
+			converted[name] = c.convertResponse(response, c.produces)
+		}
+		components["responses"] = converted
+	}
+	if len(components) > 0 {
+		out["components"] = components
+	}
+
+	paths := map[string]interface{}{}
+	for path, item := range objectField(c.src, "paths") {
+		if itemObj, ok := item.(map[string]interface{}); ok {
+			paths[path] = c.convertPathItem(path, itemObj)
+		}
+	}
+	out["paths"] = paths
+	return out
+}
+
+// servers builds the OpenAPI 3 servers from schemes, host and basePath.
+func (c *converter) servers() []interface{} {
+	host, _ := c.src["host"].(string)
+	basePath, _ := c.src["basePath"].(string)
+	if basePath == "" {
+		basePath = "/"
+	}
+	if host == "" {
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+
+	var servers []interface{}
+	for _, scheme := range stringList(c.src["schemes"], "http") {
+		url := fmt.Sprintf("%s://%s%s", scheme, host, strings.TrimSuffix(basePath, "/"))
+		servers = append(servers, map[string]interface{}{"url": url})
+	}
+	return servers
+}
+
+func (c *converter) securitySchemes() map[string]interface{} {
+	definitions := objectField(c.src, "securityDefinitions")
+	schemes := map[string]interface{}{}
+	for name, def := range definitions {
+		defObj, ok := def.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		scheme := map[string]interface{}{}
+		if description, ok := defObj["description"]; ok {
+			scheme["description"] = description
+		}
+
+		switch defObj["type"] {
+		case "basic":
+			scheme["type"] = "http"
+			scheme["scheme"] = "basic"
+		case "apiKey":
+			scheme["type"] = "apiKey"
+			scheme["name"] = defObj["name"]
+			scheme["in"] = defObj["in"]
+		case "oauth2":
+			scheme["type"] = "oauth2"
+			flow := map[string]interface{}{"scopes": defObj["scopes"]}
+			if flow["scopes"] == nil {
+				flow["scopes"] = map[string]interface{}{}
+			}
+			flowName := ""
+			switch defObj["flow"] {
+			case "implicit":
+				flowName = "implicit"
+				flow["authorizationUrl"] = defObj["authorizationUrl"]
+			case "password":
+				flowName = "password"
+				flow["tokenUrl"] = defObj["tokenUrl"]
+			case "application":
+				flowName = "clientCredentials"
+				flow["tokenUrl"] = defObj["tokenUrl"]
+			case "accessCode":
+				flowName = "authorizationCode"
+				flow["authorizationUrl"] = defObj["authorizationUrl"]
+				flow["tokenUrl"] = defObj["tokenUrl"]
+			default:
+				c.warn("security definition %q has unknown oauth2 flow %v; skipped", name, defObj["flow"])
+				continue
+			}
+			scheme["flows"] = map[string]interface{}{flowName: flow}
+		default:
+			c.warn("security definition %q has unknown type %v; skipped", name, defObj["type"])
+			continue
+		}
+		schemes[name] = scheme
+	}
+	return schemes
+}
+
+// convertGlobalParameters moves document-level parameters to components.parameters,
+// and body parameters to components.requestBodies.
+func (c *converter) convertGlobalParameters(components map[string]interface{}) {
+	parameters := map[string]interface{}{}
+	requestBodies := map[string]interface{}{}
+	for name, param := range c.globalParameters {
+		paramObj, ok := param.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch paramObj["in"] {
+		case "body":
+			requestBodies[name] = c.bodyRequest(paramObj, c.consumes)
+		case "formData":
+			c.warn("document-level formData parameter %q cannot be referenced in OpenAPI 3; it is inlined where used", name)
+		default:
+			parameters[name] = c.convertParameter(paramObj)
+		}
+	}
+	if len(parameters) > 0 {
+		components["parameters"] = parameters
+	}
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+}
+
+func (c *converter) convertPathItem(path string, item map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	pathParams, _ := item["parameters"].([]interface{})
+
+	var sharedParams, sharedBodyParams []interface{}
+	for _, param := range pathParams {
+		if c.isBodyOrForm(param) {
+			sharedBodyParams = append(sharedBodyParams, param)
+		} else {
+			sharedParams = append(sharedParams, c.convertParameterOrRef(param))
+		}
+	}
+	if len(sharedParams) > 0 {
+		out["parameters"] = sharedParams
+	}
+
+	for key, value := range item {
+		switch {
+		case key == "parameters":
+		case isHTTPMethod(key):
+			if op, ok := value.(map[string]interface{}); ok {
+				out[key] = c.convertOperation(fmt.Sprintf("%s %s", strings.ToUpper(key), path), op, sharedBodyParams)
+			}
+		default:
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func (c *converter) convertOperation(name string, op map[string]interface{}, sharedBodyParams []interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	produces := stringList(op["produces"], c.produces...)
+	opParams, _ := op["parameters"].([]interface{})
+	params, body := c.splitParameters(name, append(append([]interface{}{}, sharedBodyParams...), opParams...),
+		stringList(op["consumes"], c.consumes...))
+
+	for key, value := range op {
+		switch key {
+		case "consumes", "produces", "schemes":
+		case "parameters":
+			if len(params) > 0 {
+				out["parameters"] = params
+			}
+		case "responses":
+			responses := map[string]interface{}{}
+			for code, response := range objectField(op, "responses") {
+				responses[code] = c.convertResponse(response, produces)
+			}
+			out["responses"] = responses
+		default:
+			out[key] = value
+		}
+	}
+	if _, ok := op["schemes"]; ok {
+		c.warn("%s overrides schemes, which has no OpenAPI 3 equivalent; ignored", name)
+	}
+	if body != nil {
+		out["requestBody"] = body
+	}
+	return out
+}
+
+// splitParameters converts the parameters of an operation, moving body and formData parameters to a request body.
+func (c *converter) splitParameters(name string, all []interface{}, consumes []string) ([]interface{}, map[string]interface{}) {
+	var params, formParams []interface{}
+	var body map[string]interface{}
+	for _, param := range all {
+		paramObj, _ := param.(map[string]interface{})
+		resolved := c.resolveParameter(param)
+		switch resolved["in"] {
+		case "body":
+			if body != nil {
+				c.warn("%s has more than one body parameter; only the first is kept", name)
+				continue
+			}
+			if ref, ok := paramObj["$ref"]; ok {
+				body = map[string]interface{}{"$ref": ref}
+			} else {
+				body = c.bodyRequest(resolved, consumes)
+			}
+		case "formData":
+			formParams = append(formParams, resolved)
+		default:
+			params = append(params, c.convertParameterOrRef(param))
+		}
+	}
+	if len(formParams) > 0 {
+		if body != nil {
+			c.warn("%s mixes body and formData parameters; formData parameters are dropped", name)
+		} else {
+			body = c.formRequest(formParams, consumes)
+		}
+	}
+	return params, body
+}
+
+// resolveParameter returns the parameter object, following references to document-level parameters.
+func (c *converter) resolveParameter(param interface{}) map[string]interface{} {
+	paramObj, _ := param.(map[string]interface{})
+	if ref, ok := paramObj["$ref"].(string); ok && strings.HasPrefix(ref, "#/parameters/") {
+		resolved, _ := c.globalParameters[strings.TrimPrefix(ref, "#/parameters/")].(map[string]interface{})
+		if resolved != nil {
+			return resolved
+		}
+	}
+	return paramObj
+}
+
+func (c *converter) isBodyOrForm(param interface{}) bool {
+	in := c.resolveParameter(param)["in"]
+	return in == "body" || in == "formData"
+}
+
+func (c *converter) convertParameterOrRef(param interface{}) interface{} {
+	paramObj, _ := param.(map[string]interface{})
+	if _, ok := paramObj["$ref"]; ok {
+		return paramObj
+	}
+	return c.convertParameter(paramObj)
+}
+
+func (c *converter) convertParameter(param map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	schema := map[string]interface{}{}
+	for key, value := range param {
+		switch {
+		case key == "collectionFormat":
+			c.applyCollectionFormat(param, out, value)
+		case key == "allowEmptyValue" && param["in"] != "query":
+		case containsString(parameterSchemaFields, key):
+			schema[key] = value
+		default:
+			out[key] = value
+		}
+	}
+	convertSchema(schema)
+	out["schema"] = schema
+	return out
+}
+
+func (c *converter) applyCollectionFormat(param, out map[string]interface{}, format interface{}) {
+	switch format {
+	case "csv":
+		if param["in"] == "query" || param["in"] == "cookie" {
+			out["style"] = "form"
+			out["explode"] = false
+		}
+	case "multi":
+		out["style"] = "form"
+		out["explode"] = true
+	case "ssv":
+		out["style"] = "spaceDelimited"
+	case "pipes":
+		out["style"] = "pipeDelimited"
+	default:
+		c.warn("parameter %q uses collectionFormat %v, which has no OpenAPI 3 equivalent; using the default style", param["name"], format)
+	}
+}
+
+func (c *converter) bodyRequest(param map[string]interface{}, consumes []string) map[string]interface{} {
+	schema := param["schema"]
+	convertSchema(schema)
+	content := map[string]interface{}{}
+	for _, mediaType := range consumes {
+		content[mediaType] = map[string]interface{}{"schema": schema}
+	}
+	body := map[string]interface{}{"content": content}
+	if description, ok := param["description"]; ok {
+		body["description"] = description
+	}
+	if required, ok := param["required"]; ok {
+		body["required"] = required
+	}
+	return body
+}
+
+func (c *converter) formRequest(params []interface{}, consumes []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []interface{}
+	hasFile := false
+	for _, param := range params {
+		paramObj := param.(map[string]interface{})
+		name, _ := paramObj["name"].(string)
+		schema := c.convertParameter(paramObj)["schema"].(map[string]interface{})
+		if description, ok := paramObj["description"]; ok {
+			schema["description"] = description
+		}
+		if schema["format"] == "binary" {
+			hasFile = true
+		}
+		properties[name] = schema
+		if paramObj["required"] == true {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	mediaTypes := []string{}
+	for _, mediaType := range consumes {
+		if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		if hasFile {
+			mediaTypes = []string{"multipart/form-data"}
+		} else {
+			mediaTypes = []string{"application/x-www-form-urlencoded"}
+		}
+	}
+
+	content := map[string]interface{}{}
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = map[string]interface{}{"schema": schema}
+	}
+	return map[string]interface{}{"content": content}
+}
+
+func (c *converter) convertResponse(response interface{}, produces []string) interface{} {
+	responseObj, ok := response.(map[string]interface{})
+	if !ok {
+		return response
+	}
+	if _, ok := responseObj["$ref"]; ok {
+		return responseObj
+	}
+
+	out := map[string]interface{}{}
+	for key, value := range responseObj {
+		switch key {
+		case "schema", "examples":
+		case "headers":
+			headers := map[string]interface{}{}
+			for name, header := range objectField(responseObj, "headers") {
+				if headerObj, ok := header.(map[string]interface{}); ok {
+					converted := c.convertParameter(headerObj)
+					delete(converted, "name")
+					delete(converted, "in")
+					headers[name] = converted
+				}
+			}
+			out["headers"] = headers
+		default:
+			out[key] = value
+		}
+	}
+	if _, ok := out["description"]; !ok {
+		out["description"] = ""
+	}
+
+	schema, hasSchema := responseObj["schema"]
+	examples := objectField(responseObj, "examples")
+	if !hasSchema && len(examples) == 0 {
+		return out
+	}
+	convertSchema(schema)
+	content := map[string]interface{}{}
+	for _, mediaType := range produces {
+		media := map[string]interface{}{}
+		if hasSchema {
+			media["schema"] = schema
+		}
+		if example, ok := examples[mediaType]; ok {
+			media["example"] = example
+		}
+		content[mediaType] = media
+	}
+	for mediaType, example := range examples {
+		if _, ok := content[mediaType]; !ok {
+			content[mediaType] = map[string]interface{}{"schema": schema, "example": example}
+		}
+	}
+	out["content"] = content
+	return out
+}
+
+// convertSchema rewrites Swagger 2.0 schema constructs in place.
+func convertSchema(schema interface{}) {
+	walkJSON(schema, func(obj map[string]interface{}) {
+		if nullable, ok := obj["x-nullable"]; ok {
+			obj["nullable"] = nullable
+			delete(obj, "x-nullable")
+		}
+		if obj["type"] == "file" {
+			obj["type"] = "string"
+			obj["format"] = "binary"
+		}
+		if discriminator, ok := obj["discriminator"].(string); ok {
+			obj["discriminator"] = map[string]interface{}{"propertyName": discriminator}
+		}
+	})
+}
+
+// rewriteSwagger2Refs points local references at their OpenAPI 3 component locations.
+func rewriteSwagger2Refs(node interface{}, globalParameters map[string]interface{}) {
+	walkJSON(node, func(obj map[string]interface{}) {
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return
+		}
+		switch {
+		case strings.HasPrefix(ref, "#/definitions/"):
+			obj["$ref"] = "#/components/schemas/" + strings.TrimPrefix(ref, "#/definitions/")
+		case strings.HasPrefix(ref, "#/responses/"):
+			obj["$ref"] = "#/components/responses/" + strings.TrimPrefix(ref, "#/responses/")
+		case strings.HasPrefix(ref, "#/parameters/"):
+			name := strings.TrimPrefix(ref, "#/parameters/")
+			if param, ok := globalParameters[name].(map[string]interface{}); ok && param["in"] == "body" {
+				obj["$ref"] = "#/components/requestBodies/" + name
+			} else {
+				obj["$ref"] = "#/components/parameters/" + name
+			}
+		}
+	})
+}
+
+func isHTTPMethod(key string) bool {
+	return containsString(HTTPMethods, key)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// objectField returns obj[key] as a JSON object, or nil.
+func objectField(obj map[string]interface{}, key string) map[string]interface{} {
+	value, _ := obj[key].(map[string]interface{})
+	return value
+}
+
+// stringList returns value as a list of strings, or defaults if it is not a non-empty list.
+func stringList(value interface{}, defaults ...string) []string {
+	list, _ := value.([]interface{})
+	var out []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return defaults
+	}
+	return out
+}
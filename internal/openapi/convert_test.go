@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const springDoc = `{
+  "swagger": "2.0",
+  "info": {"title": "Billing", "version": "1.4.0"},
+  "host": "billing.internal:8080",
+  "basePath": "/billing",
+  "schemes": ["https"],
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "securityDefinitions": {
+    "oauth": {"type": "oauth2", "flow": "application", "tokenUrl": "https://auth/token", "scopes": {"read": "read"}},
+    "basic": {"type": "basic"}
+  },
+  "parameters": {
+    "tenant": {"name": "X-Tenant", "in": "header", "type": "string", "required": true}
+  },
+  "paths": {
+    "/invoices": {
+      "parameters": [{"$ref": "#/parameters/tenant"}],
+      "get": {
+        "parameters": [{"name": "status", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}],
+        "responses": {"200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Invoice"}}}}
+      },
+      "post": {
+        "parameters": [{"name": "invoice", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Invoice"}}],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/invoices/{id}/attachment": {
+      "put": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "type": "string"},
+          {"name": "file", "in": "formData", "required": true, "type": "file"},
+          {"name": "sep", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "tsv"}
+        ],
+        "responses": {"204": {"description": "stored"}}
+      }
+    }
+  },
+  "definitions": {
+    "Invoice": {"type": "object", "properties": {"note": {"type": "string", "x-nullable": true}}}
+  }
+}`
+
+var _ = Describe("ConvertSwagger2", func() {
+	var (
+		converted *Document
+		warnings  []string
+		paths     map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		var err error
+		converted, warnings, err = ConvertSwagger2(mustParse(springDoc))
+		Expect(err).NotTo(HaveOccurred())
+		paths = converted.Content["paths"].(map[string]interface{})
+	})
+
+	It("produces an OpenAPI 3.0 document that parses", func() {
+		Expect(converted.SpecVersion).To(Equal(ConvertedSpecVersion))
+		data, err := converted.MarshalJSON()
+		Expect(err).NotTo(HaveOccurred())
+		reparsed, err := Parse(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reparsed.Title).To(Equal("Billing"))
+	})
+
+	It("builds servers from schemes, host and basePath", func() {
+		Expect(converted.Content["servers"]).To(ConsistOf(map[string]interface{}{"url": "https://billing.internal:8080/billing"}))
+	})
+
+	It("moves definitions and security definitions to components", func() {
+		components := converted.Content["components"].(map[string]interface{})
+		schema := components["schemas"].(map[string]interface{})["Invoice"].(map[string]interface{})
+		note := schema["properties"].(map[string]interface{})["note"]
+		Expect(note).To(HaveKeyWithValue("nullable", true))
+
+		schemes := components["securitySchemes"].(map[string]interface{})
+		Expect(schemes["basic"]).To(Equal(map[string]interface{}{"type": "http", "scheme": "basic"}))
+		Expect(schemes["oauth"].(map[string]interface{})["flows"]).To(HaveKey("clientCredentials"))
+		Expect(components["parameters"]).To(HaveKey("tenant"))
+	})
+
+	It("converts body parameters and response schemas to content", func() {
+		post := paths["/invoices"].(map[string]interface{})["post"].(map[string]interface{})
+		body := post["requestBody"].(map[string]interface{})
+		Expect(body).To(HaveKeyWithValue("required", true))
+		media := body["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+		Expect(media["schema"]).To(HaveKeyWithValue("$ref", "#/components/schemas/Invoice"))
+
+		get := paths["/invoices"].(map[string]interface{})["get"].(map[string]interface{})
+		response := get["responses"].(map[string]interface{})["200"].(map[string]interface{})
+		Expect(response["content"]).To(HaveKey("application/json"))
+		param := get["parameters"].([]interface{})[0].(map[string]interface{})
+		Expect(param).To(HaveKeyWithValue("style", "form"))
+		Expect(param).To(HaveKeyWithValue("explode", true))
+		Expect(param["schema"]).To(HaveKeyWithValue("type", "array"))
+
+		Expect(paths["/invoices"].(map[string]interface{})["parameters"]).To(ConsistOf(
+			map[string]interface{}{"$ref": "#/components/parameters/tenant"}))
+	})
+
+	It("converts formData parameters to a multipart request body", func() {
+		put := paths["/invoices/{id}/attachment"].(map[string]interface{})["put"].(map[string]interface{})
+		content := put["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+		schema := content["multipart/form-data"].(map[string]interface{})["schema"].(map[string]interface{})
+		Expect(schema["properties"]).To(HaveKeyWithValue("file", map[string]interface{}{"type": "string", "format": "binary"}))
+		Expect(schema["required"]).To(ConsistOf("file"))
+		Expect(put["parameters"]).To(HaveLen(2))
+	})
+
+	It("reports constructs without an OpenAPI 3 equivalent", func() {
+		Expect(warnings).To(ConsistOf(ContainSubstring(`collectionFormat tsv`)))
+	})
+
+	It("rejects documents that are already OpenAPI 3", func() {
+		_, _, err := ConvertSwagger2(mustParse(openAPI3Doc))
+		Expect(err).To(HaveOccurred())
+	})
+})
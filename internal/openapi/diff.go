@@ -0,0 +1,328 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxRefDepth bounds how many $refs are followed when resolving an object.
+const maxRefDepth = 16
+
+// Change is a difference between two revisions of a spec.
+type Change struct {
+	// Breaking reports whether clients written against the previous revision may fail against the new one
+	Breaking bool
+
+	// Location is where the change was found, e.g. "GET /pets/{id}"
+	Location string
+
+	// Message describes the change
+	Message string
+}
+
+// Diff compares two revisions of a spec and classifies their differences. Removed paths and operations,
+// new required parameters and request bodies, parameters that became required, enum values removed from
+// parameters and request bodies, removed success responses, and response media types or schema types
+// that changed are breaking; added paths, operations and optional parameters are not. The changes are
+// returned ordered by location.
+func Diff(previous, current *Document) []Change {
+	d := &differ{previous: previous, current: current}
+	d.paths()
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		if d.changes[i].Location != d.changes[j].Location {
+			return d.changes[i].Location < d.changes[j].Location
+		}
+		return d.changes[i].Message < d.changes[j].Message
+	})
+	return d.changes
+}
+
+// differ accumulates the changes between two documents.
+type differ struct {
+	previous *Document
+	current  *Document
+	changes  []Change
+}
+
+func (d *differ) add(breaking bool, location, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Breaking: breaking, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+// paths compares the path items of both documents.
+func (d *differ) paths() {
+	previousPaths := objectField(d.previous.Content, "paths")
+	currentPaths := objectField(d.current.Content, "paths")
+	for _, path := range sortedKeys(previousPaths) {
+		previousItem := resolve(d.previous, previousPaths[path])
+		currentItem, ok := currentPaths[path]
+		if !ok {
+			d.add(true, path, "path removed")
+			continue
+		}
+		d.operations(path, previousItem, resolve(d.current, currentItem))
+	}
+	for _, path := range sortedKeys(currentPaths) {
+		if _, ok := previousPaths[path]; !ok {
+			d.add(false, path, "path added")
+		}
+	}
+}
+
+// operations compares the operations of a path item.
+func (d *differ) operations(path string, previousItem, currentItem map[string]interface{}) {
+	for _, method := range HTTPMethods {
+		previousOp, hadOp := previousItem[method].(map[string]interface{})
+		currentOp, hasOp := currentItem[method].(map[string]interface{})
+		location := strings.ToUpper(method) + " " + path
+		switch {
+		case hadOp && !hasOp:
+			d.add(true, location, "operation removed")
+		case !hadOp && hasOp:
+			d.add(false, location, "operation added")
+		case hadOp && hasOp:
+			d.parameters(location, parameters(d.previous, previousItem, previousOp), parameters(d.current, currentItem, currentOp))
+			d.requestBody(location, previousOp, currentOp)
+			d.responses(location, previousOp, currentOp)
+		}
+	}
+}
+
+// parameters compares the parameters of an operation, keyed by location and name.
+func (d *differ) parameters(location string, previous, current map[string]map[string]interface{}) {
+	for _, key := range sortedParameterKeys(current) {
+		param := current[key]
+		previousParam, existed := previous[key]
+		required, _ := param["required"].(bool)
+		switch {
+		case !existed && required:
+			d.add(true, location, "required parameter %s added", key)
+		case !existed:
+			d.add(false, location, "optional parameter %s added", key)
+		default:
+			if wasRequired, _ := previousParam["required"].(bool); required && !wasRequired {
+				d.add(true, location, "parameter %s became required", key)
+			}
+			d.enum(location, "parameter "+key, parameterEnum(d.previous, previousParam), parameterEnum(d.current, param))
+		}
+	}
+	for _, key := range sortedParameterKeys(previous) {
+		if _, ok := current[key]; !ok {
+			d.add(false, location, "parameter %s removed", key)
+		}
+	}
+}
+
+// requestBody compares the OpenAPI 3 request bodies of an operation.
+func (d *differ) requestBody(location string, previousOp, currentOp map[string]interface{}) {
+	previousBody := resolve(d.previous, previousOp["requestBody"])
+	currentBody := resolve(d.current, currentOp["requestBody"])
+	if currentBody == nil {
+		return
+	}
+	required, _ := currentBody["required"].(bool)
+	wasRequired, _ := previousBody["required"].(bool)
+	switch {
+	case required && previousBody == nil:
+		d.add(true, location, "required request body added")
+	case required && !wasRequired:
+		d.add(true, location, "request body became required")
+	}
+
+	previousContent := objectField(previousBody, "content")
+	currentContent := objectField(currentBody, "content")
+	for _, mediaType := range sortedKeys(currentContent) {
+		previousMedia, ok := previousContent[mediaType].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		currentMedia, _ := currentContent[mediaType].(map[string]interface{})
+		previousSchema := resolve(d.previous, previousMedia["schema"])
+		currentSchema := resolve(d.current, currentMedia["schema"])
+		previousProperties := objectField(previousSchema, "properties")
+		currentProperties := objectField(currentSchema, "properties")
+		for _, name := range sortedKeys(currentProperties) {
+			if previousProperty, ok := previousProperties[name]; ok {
+				d.enum(location, "request property "+name, stringSet(resolve(d.previous, previousProperty)["enum"]),
+					stringSet(resolve(d.current, currentProperties[name])["enum"]))
+			}
+		}
+	}
+}
+
+// enum reports values removed from an enum of a request element; values may be added freely.
+func (d *differ) enum(location, element string, previous, current map[string]bool) {
+	if len(previous) == 0 {
+		if len(current) > 0 {
+			d.add(true, location, "%s is now restricted to an enum", element)
+		}
+		return
+	}
+	if len(current) == 0 {
+		return
+	}
+	var removed []string
+	for value := range previous {
+		if !current[value] {
+			removed = append(removed, value)
+		}
+	}
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		d.add(true, location, "%s no longer accepts %s", element, strings.Join(removed, ", "))
+	}
+}
+
+// responses compares the responses of an operation.
+func (d *differ) responses(location string, previousOp, currentOp map[string]interface{}) {
+	previousResponses := objectField(previousOp, "responses")
+	currentResponses := objectField(currentOp, "responses")
+	for _, code := range sortedKeys(previousResponses) {
+		currentResponse, ok := currentResponses[code]
+		if !ok {
+			d.add(strings.HasPrefix(code, "2"), location, "response %s removed", code)
+			continue
+		}
+		previousMedia := responseMedia(d.previous, previousOp, previousResponses[code])
+		currentMedia := responseMedia(d.current, currentOp, currentResponse)
+		for _, mediaType := range sortedKeys(previousMedia) {
+			currentSchema, ok := currentMedia[mediaType]
+			if !ok {
+				d.add(true, location, "response %s no longer returns %s", code, mediaType)
+				continue
+			}
+			previousType := schemaType(d.previous, previousMedia[mediaType])
+			currentType := schemaType(d.current, currentSchema)
+			if previousType != "" && currentType != "" && previousType != currentType {
+				d.add(true, location, "response %s %s changed from %s to %s", code, mediaType, previousType, currentType)
+			}
+		}
+	}
+	for _, code := range sortedKeys(currentResponses) {
+		if _, ok := previousResponses[code]; !ok {
+			d.add(false, location, "response %s added", code)
+		}
+	}
+}
+
+// parameters returns the parameters of an operation, including those declared on its path item,
+// keyed by "<in> <name>".
+func parameters(doc *Document, item, op map[string]interface{}) map[string]map[string]interface{} {
+	params := map[string]map[string]interface{}{}
+	for _, list := range []interface{}{item["parameters"], op["parameters"]} {
+		entries, _ := list.([]interface{})
+		for _, entry := range entries {
+			param := resolve(doc, entry)
+			name, _ := param["name"].(string)
+			in, _ := param["in"].(string)
+			if name == "" {
+				continue
+			}
+			params[in+" "+name] = param
+		}
+	}
+	return params
+}
+
+// parameterEnum returns the enum values a parameter accepts, declared on it (Swagger 2.0) or on its schema.
+func parameterEnum(doc *Document, param map[string]interface{}) map[string]bool {
+	if values := stringSet(param["enum"]); len(values) > 0 {
+		return values
+	}
+	schema := resolve(doc, param["schema"])
+	if values := stringSet(schema["enum"]); len(values) > 0 {
+		return values
+	}
+	return stringSet(resolve(doc, schema["items"])["enum"])
+}
+
+// responseMedia returns the schema of a response by media type. Swagger 2.0 responses use the
+// operation's or document's produces list, or "*/*" if none is declared.
+func responseMedia(doc *Document, op map[string]interface{}, response interface{}) map[string]interface{} {
+	resolved := resolve(doc, response)
+	if !doc.IsSwagger2() {
+		media := map[string]interface{}{}
+		for mediaType, value := range objectField(resolved, "content") {
+			content, _ := value.(map[string]interface{})
+			media[mediaType] = content["schema"]
+		}
+		return media
+	}
+
+	schema, ok := resolved["schema"]
+	if !ok {
+		return nil
+	}
+	produces := stringList(op["produces"], stringList(doc.Content["produces"], "*/*")...)
+	media := make(map[string]interface{}, len(produces))
+	for _, mediaType := range produces {
+		media[mediaType] = schema
+	}
+	return media
+}
+
+// schemaType describes the type of a schema, including the item type of arrays.
+func schemaType(doc *Document, schema interface{}) string {
+	resolved := resolve(doc, schema)
+	typ, _ := resolved["type"].(string)
+	if typ == "array" {
+		if itemType := schemaType(doc, resolved["items"]); itemType != "" {
+			return "array of " + itemType
+		}
+	}
+	return typ
+}
+
+// resolve returns node as a JSON object, following local $refs.
+func resolve(doc *Document, node interface{}) map[string]interface{} {
+	obj, _ := node.(map[string]interface{})
+	for depth := 0; obj != nil && depth < maxRefDepth; depth++ {
+		ref, ok := obj["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return obj
+		}
+		var target interface{} = doc.Content
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			parent, _ := target.(map[string]interface{})
+			target = parent[unescapePointer(token)]
+		}
+		obj, _ = target.(map[string]interface{})
+	}
+	return obj
+}
+
+// stringSet returns the values of a JSON array formatted as strings.
+func stringSet(value interface{}) map[string]bool {
+	list, _ := value.([]interface{})
+	set := make(map[string]bool, len(list))
+	for _, item := range list {
+		set[fmt.Sprint(item)] = true
+	}
+	return set
+}
+
+// sortedParameterKeys returns the keys of a parameter map in lexical order.
+func sortedParameterKeys(params map[string]map[string]interface{}) []string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
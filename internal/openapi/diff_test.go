@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const petsV1 = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"name": "limit", "in": "query", "schema": {"type": "integer"}},
+          {"$ref": "#/components/parameters/Status"}
+        ],
+        "responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}}}
+      },
+      "post": {
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}": {
+      "delete": {"responses": {"204": {"description": "deleted"}}}
+    }
+  },
+  "components": {
+    "parameters": {
+      "Status": {"name": "status", "in": "query", "schema": {"type": "string", "enum": ["available", "pending", "sold"]}}
+    },
+    "schemas": {
+      "Pet": {"type": "object", "properties": {"kind": {"type": "string", "enum": ["cat", "dog"]}}}
+    }
+  }
+}`
+
+const petsV2 = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "2"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"name": "limit", "in": "query", "schema": {"type": "integer"}},
+          {"name": "owner", "in": "query", "required": true, "schema": {"type": "string"}},
+          {"name": "sort", "in": "query", "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/Status"}
+        ],
+        "responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"type": "object"}}}}}
+      },
+      "post": {
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/owners": {
+      "get": {"responses": {"200": {"description": "ok"}}}
+    }
+  },
+  "components": {
+    "parameters": {
+      "Status": {"name": "status", "in": "query", "schema": {"type": "string", "enum": ["available", "sold", "reserved"]}}
+    },
+    "schemas": {
+      "Pet": {"type": "object", "properties": {"kind": {"type": "string", "enum": ["dog"]}}}
+    }
+  }
+}`
+
+var _ = Describe("Diff", func() {
+	It("classifies breaking and non-breaking changes", func() {
+		changes := Diff(mustParse(petsV1), mustParse(petsV2))
+
+		var breaking, nonBreaking []string
+		for _, change := range changes {
+			if change.Breaking {
+				breaking = append(breaking, change.Location+": "+change.Message)
+			} else {
+				nonBreaking = append(nonBreaking, change.Location+": "+change.Message)
+			}
+		}
+		Expect(breaking).To(ConsistOf(
+			"/pets/{id}: path removed",
+			"GET /pets: required parameter query owner added",
+			"GET /pets: parameter query status no longer accepts pending",
+			"GET /pets: response 200 application/json changed from array of object to object",
+			"POST /pets: request body became required",
+			"POST /pets: request property kind no longer accepts cat",
+		))
+		Expect(nonBreaking).To(ConsistOf(
+			"/owners: path added",
+			"GET /pets: optional parameter query sort added",
+		))
+	})
+
+	It("reports no changes between identical documents", func() {
+		Expect(Diff(mustParse(petsV1), mustParse(petsV1))).To(BeEmpty())
+	})
+
+	It("reports removed and added operations and success responses", func() {
+		previous := mustParse(`{"swagger": "2.0", "info": {"title": "T", "version": "1"}, "produces": ["application/json"], "paths": {
+		  "/a": {"get": {"responses": {"200": {"description": "ok", "schema": {"type": "string"}}, "404": {"description": "missing"}}},
+		         "put": {"responses": {"204": {"description": "ok"}}}}}}`)
+		current := mustParse(`{"swagger": "2.0", "info": {"title": "T", "version": "2"}, "produces": ["application/json"], "paths": {
+		  "/a": {"get": {"responses": {"200": {"description": "ok", "schema": {"type": "integer"}}}},
+		         "post": {"responses": {"201": {"description": "ok"}}}}}}`)
+
+		Expect(Diff(previous, current)).To(Equal([]Change{
+			{Breaking: true, Location: "GET /a", Message: "response 200 application/json changed from string to integer"},
+			{Breaking: false, Location: "GET /a", Message: "response 404 removed"},
+			{Breaking: false, Location: "POST /a", Message: "operation added"},
+			{Breaking: true, Location: "PUT /a", Message: "operation removed"},
+		}))
+	})
+})
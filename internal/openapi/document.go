@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openapi fetches, parses and transforms Swagger 2.0 and OpenAPI 3.x documents.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Document is a parsed Swagger 2.0 or OpenAPI 3.x document.
+// The content is kept as generic JSON so that fields this package does not know about survive rewrites.
+type Document struct {
+	// Content is the decoded top-level JSON object of the document
+	Content map[string]interface{}
+
+	// SpecVersion is the value of the "swagger" or "openapi" field
+	SpecVersion string
+
+	// Title is the value of info.title
+	Title string
+
+	// Version is the value of info.version
+	Version string
+}
+
+// IsSwagger2 reports whether the document is a Swagger 2.0 document.
+func (d *Document) IsSwagger2() bool {
+	return d.SpecVersion == "2.0"
+}
+
+// IsOpenAPI3 reports whether the document is an OpenAPI 3.x document.
+func (d *Document) IsOpenAPI3() bool {
+	return strings.HasPrefix(d.SpecVersion, "3.")
+}
+
+// DeepCopy returns a copy of the document that shares no content with the original.
+func (d *Document) DeepCopy() *Document {
+	out := *d
+	out.Content, _ = deepCopyJSON(d.Content).(map[string]interface{})
+	return &out
+}
+
+// MarshalJSON returns the JSON encoding of the document content.
+func (d *Document) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Content)
+}
+
+// Parse decodes a JSON or YAML document and validates that it is a Swagger 2.0 or OpenAPI 3.x document.
+func Parse(data []byte) (*Document, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("document is empty")
+	}
+
+	if data[0] != '{' {
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("document is neither valid JSON nor YAML: %w", err)
+		}
+		data = converted
+	}
+
+	var content map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keep numbers as json.Number so that large integers in examples and enums are not rounded.
+	decoder.UseNumber()
+	if err := decoder.Decode(&content); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if content == nil {
+		return nil, fmt.Errorf("document is not a JSON object")
+	}
+
+	doc := &Document{Content: content}
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// validate checks the fields every supported document must have and fills in the summary fields.
+func (d *Document) validate() error {
+	swagger, hasSwagger := d.Content["swagger"]
+	openapi, hasOpenAPI := d.Content["openapi"]
+
+	switch {
+	case hasSwagger:
+		version, _ := swagger.(string)
+		if version != "2.0" {
+			return fmt.Errorf("unsupported swagger version %v", swagger)
+		}
+		d.SpecVersion = version
+	case hasOpenAPI:
+		version, _ := openapi.(string)
+		if !strings.HasPrefix(version, "3.") {
+			return fmt.Errorf("unsupported openapi version %v", openapi)
+		}
+		d.SpecVersion = version
+	default:
+		return fmt.Errorf("document has neither a swagger nor an openapi version field")
+	}
+
+	info, ok := d.Content["info"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("document has no info object")
+	}
+	d.Title, _ = info["title"].(string)
+	if d.Title == "" {
+		return fmt.Errorf("info.title is required")
+	}
+	d.Version, _ = info["version"].(string)
+	if d.Version == "" {
+		return fmt.Errorf("info.version is required")
+	}
+
+	// OpenAPI 3.1 allows documents that only describe webhooks or components.
+	if strings.HasPrefix(d.SpecVersion, "3.1") {
+		for _, field := range []string{"paths", "webhooks", "components"} {
+			if _, ok := d.Content[field].(map[string]interface{}); ok {
+				return nil
+			}
+		}
+		return fmt.Errorf("document must contain at least one of paths, webhooks or components")
+	}
+	if _, ok := d.Content["paths"].(map[string]interface{}); !ok {
+		return fmt.Errorf("document has no paths object")
+	}
+	return nil
+}
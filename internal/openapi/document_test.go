@@ -0,0 +1,35 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Document", func() {
+	It("deep copies content so transforms do not affect the original", func() {
+		original := mustParse(crudDoc)
+		copied := original.DeepCopy()
+		Expect(copied.Title).To(Equal(original.Title))
+
+		PruneOperations(copied, []string{"get"})
+		Expect(original.Content["paths"]).To(HaveLen(2))
+		Expect(original.Content["paths"].(map[string]interface{})["/accounts"]).To(HaveKey("post"))
+		Expect(copied.Content["paths"]).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,305 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the default timeout for fetching a single document
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultMaxConcurrency is the default number of documents fetched in parallel
+	DefaultMaxConcurrency = 8
+
+	// DefaultMaxBodyBytes is the default upper bound for the size of a fetched document
+	DefaultMaxBodyBytes int64 = 16 << 20
+)
+
+// Reasons a fetch can fail, as reported in Result.Reason.
+const (
+	ReasonInvalidURL      = "invalid_url"
+	ReasonUnreachable     = "unreachable"
+	ReasonTLS             = "tls_error"
+	ReasonTimeout         = "timeout"
+	ReasonHTTPStatus      = "http_status"
+	ReasonReadFailed      = "read_failed"
+	ReasonTooLarge        = "too_large"
+	ReasonInvalidDocument = "invalid_document"
+)
+
+// FetcherOptions configures a Fetcher
+type FetcherOptions struct {
+	// Timeout bounds a single fetch, including reading the body
+	Timeout time.Duration
+
+	// MaxConcurrency is the maximum number of documents fetched in parallel by FetchAll
+	MaxConcurrency int
+
+	// MaxBodyBytes is the maximum accepted document size
+	MaxBodyBytes int64
+
+	// HTTPClient overrides the client used for requests. Its Timeout is left untouched.
+	HTTPClient *http.Client
+}
+
+// Fetcher downloads and parses OpenAPI documents over HTTP
+type Fetcher struct {
+	client         *http.Client
+	timeout        time.Duration
+	maxConcurrency int
+	maxBodyBytes   int64
+
+	// tlsClients holds a client per TLS setting requested, derived from client
+	mu         sync.Mutex
+	tlsClients map[tlsSettings]*http.Client
+}
+
+// Request describes a document to fetch
+type Request struct {
+	// URL is the address the document is served from
+	URL string
+
+	// TLSServerName is the name the server certificate is verified against instead of the URL's host
+	TLSServerName string
+
+	// InsecureSkipVerify disables verification of the server certificate
+	InsecureSkipVerify bool
+}
+
+// tlsSettings are the TLS settings of a Request
+type tlsSettings struct {
+	serverName         string
+	insecureSkipVerify bool
+}
+
+// Result is the outcome of fetching a single document
+type Result struct {
+	// StatusCode is the HTTP status returned by the server, or zero if no response was received
+	StatusCode int
+
+	// Body is the raw document as served
+	Body []byte
+
+	// Document is the parsed document; nil if Err is set
+	Document *Document
+
+	// Err describes why the document could not be fetched or parsed
+	Err error
+
+	// Reason classifies Err as one of the Reason constants; empty if Err is nil
+	Reason string
+
+	// Duration is how long the fetch took, including reading and parsing the body
+	Duration time.Duration
+}
+
+// NewFetcher creates a Fetcher, applying defaults for unset options.
+func NewFetcher(opts FetcherOptions) *Fetcher {
+	f := &Fetcher{
+		client:         opts.HTTPClient,
+		timeout:        opts.Timeout,
+		maxConcurrency: opts.MaxConcurrency,
+		maxBodyBytes:   opts.MaxBodyBytes,
+	}
+	if f.timeout <= 0 {
+		f.timeout = DefaultTimeout
+	}
+	if f.maxConcurrency <= 0 {
+		f.maxConcurrency = DefaultMaxConcurrency
+	}
+	if f.maxBodyBytes <= 0 {
+		f.maxBodyBytes = DefaultMaxBodyBytes
+	}
+	client := http.Client{}
+	if f.client != nil {
+		client = *f.client
+	}
+	if client.CheckRedirect == nil {
+		client.CheckRedirect = sameHostRedirect
+	}
+	f.client = &client
+	return f
+}
+
+// maxRedirects is the number of redirects followed when fetching a document.
+const maxRedirects = 10
+
+// sameHostRedirect follows redirects to the host of the original request only, so that a document cannot
+// send the fetcher to an address it was not configured to fetch from.
+func sameHostRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if req.URL.Host != via[0].URL.Host {
+		return fmt.Errorf("redirect to another host %q is not followed", req.URL.Host)
+	}
+	return nil
+}
+
+// Fetch downloads and parses a single document.
+func (f *Fetcher) Fetch(ctx context.Context, req Request) Result {
+	start := time.Now()
+	result := f.fetch(ctx, req)
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (f *Fetcher) fetch(ctx context.Context, req Request) Result {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL, nil)
+	if err != nil {
+		return Result{Err: fmt.Errorf("invalid OpenAPI endpoint URL: %w", err), Reason: ReasonInvalidURL}
+	}
+	if httpReq.URL.Scheme != "http" && httpReq.URL.Scheme != "https" {
+		return Result{Err: fmt.Errorf("invalid OpenAPI endpoint URL %q: scheme must be http or https", req.URL), Reason: ReasonInvalidURL}
+	}
+	httpReq.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.8")
+
+	resp, err := f.clientFor(req).Do(httpReq)
+	if err != nil {
+		return Result{Err: fmt.Errorf("failed to access OpenAPI endpoint: %w", err), Reason: transportReason(err)}
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	result := Result{StatusCode: resp.StatusCode}
+	if resp.StatusCode != http.StatusOK {
+		result.Err = fmt.Errorf("OpenAPI endpoint returned non-200 status: %d", resp.StatusCode)
+		result.Reason = ReasonHTTPStatus
+		return result
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBodyBytes+1))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to read OpenAPI document: %w", err)
+		result.Reason = transportReason(err)
+		if result.Reason == ReasonUnreachable {
+			result.Reason = ReasonReadFailed
+		}
+		return result
+	}
+	if int64(len(body)) > f.maxBodyBytes {
+		result.Err = fmt.Errorf("OpenAPI document exceeds the maximum size of %d bytes", f.maxBodyBytes)
+		result.Reason = ReasonTooLarge
+		return result
+	}
+	result.Body = body
+
+	doc, err := Parse(body)
+	if err != nil {
+		result.Err = fmt.Errorf("invalid OpenAPI document: %w", err)
+		result.Reason = ReasonInvalidDocument
+		return result
+	}
+	result.Document = doc
+	return result
+}
+
+// FetchAll fetches the given documents with bounded concurrency.
+// The returned results are in the same order as the requests.
+func (f *Fetcher) FetchAll(ctx context.Context, reqs []Request) []Result {
+	results := make([]Result, len(reqs))
+	sem := make(chan struct{}, f.maxConcurrency)
+	var wg sync.WaitGroup
+
+	for i := range reqs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = Result{Err: ctx.Err(), Reason: transportReason(ctx.Err())}
+				return
+			}
+			defer func() { <-sem }()
+			results[i] = f.Fetch(ctx, reqs[i])
+		}(i)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// clientFor returns the client to send a request with: the Fetcher's client, or a copy of it whose transport
+// applies the TLS settings of the request. Settings are ignored if the client's transport is not an *http.Transport.
+func (f *Fetcher) clientFor(req Request) *http.Client {
+	settings := tlsSettings{serverName: req.TLSServerName, insecureSkipVerify: req.InsecureSkipVerify}
+	if settings == (tlsSettings{}) {
+		return f.client
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if client, ok := f.tlsClients[settings]; ok {
+		return client
+	}
+
+	base := f.client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return f.client
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.ServerName = settings.serverName
+	// Skipping verification is opted into per Service, for backends serving self-signed certificates.
+	transport.TLSClientConfig.InsecureSkipVerify = settings.insecureSkipVerify //nolint:gosec
+
+	client := *f.client
+	client.Transport = transport
+	if f.tlsClients == nil {
+		f.tlsClients = map[tlsSettings]*http.Client{}
+	}
+	f.tlsClients[settings] = &client
+	return &client
+}
+
+// transportReason classifies an error returned while sending a request or reading its response.
+func transportReason(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ReasonTimeout
+	}
+	var verifyErr *tls.CertificateVerificationError
+	var hostnameErr x509.HostnameError
+	var authorityErr x509.UnknownAuthorityError
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &verifyErr) || errors.As(err, &hostnameErr) || errors.As(err, &authorityErr) || errors.As(err, &recordErr) {
+		return ReasonTLS
+	}
+	return ReasonUnreachable
+}
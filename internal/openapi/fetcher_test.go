@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const swagger2Doc = `{
+  "swagger": "2.0",
+  "info": {"title": "Pet Store", "version": "1.0.0"},
+  "basePath": "/api",
+  "paths": {"/pets": {"get": {"responses": {"200": {"description": "ok"}}}}}
+}`
+
+const openAPI3Doc = `openapi: 3.1.0
+info:
+  title: Orders
+  version: 2.3.4
+paths:
+  /orders:
+    get:
+      responses:
+        "200":
+          description: ok
+`
+
+var _ = Describe("Fetcher", func() {
+	var (
+		server  *httptest.Server
+		fetcher *Fetcher
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v2/api-docs", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(swagger2Doc))
+		})
+		mux.HandleFunc("/openapi.yaml", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(openAPI3Doc))
+		})
+		mux.HandleFunc("/broken", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"openapi": "3.0.0", "paths": {}}`))
+		})
+		mux.HandleFunc("/html", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`<html><body>login</body></html>`))
+		})
+		server = httptest.NewServer(mux)
+		fetcher = NewFetcher(FetcherOptions{Timeout: 2 * time.Second})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("parses a Swagger 2.0 JSON document", func() {
+		result := fetcher.Fetch(context.Background(), Request{URL: server.URL + "/v2/api-docs"})
+		Expect(result.Err).NotTo(HaveOccurred())
+		Expect(result.StatusCode).To(Equal(http.StatusOK))
+		Expect(result.Document.IsSwagger2()).To(BeTrue())
+		Expect(result.Document.Title).To(Equal("Pet Store"))
+		Expect(result.Document.Version).To(Equal("1.0.0"))
+		Expect(result.Reason).To(BeEmpty())
+		Expect(result.Duration).To(BeNumerically(">", 0))
+	})
+
+	It("parses an OpenAPI 3.1 YAML document", func() {
+		result := fetcher.Fetch(context.Background(), Request{URL: server.URL + "/openapi.yaml"})
+		Expect(result.Err).NotTo(HaveOccurred())
+		Expect(result.Document.IsOpenAPI3()).To(BeTrue())
+		Expect(result.Document.SpecVersion).To(Equal("3.1.0"))
+		Expect(result.Document.Title).To(Equal("Orders"))
+	})
+
+	It("reports non-200 responses with their status code", func() {
+		result := fetcher.Fetch(context.Background(), Request{URL: server.URL + "/missing"})
+		Expect(result.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(result.Err).To(MatchError(ContainSubstring("non-200 status: 404")))
+		Expect(result.Reason).To(Equal(ReasonHTTPStatus))
+		Expect(result.Document).To(BeNil())
+	})
+
+	It("reports documents that fail validation", func() {
+		result := fetcher.Fetch(context.Background(), Request{URL: server.URL + "/broken"})
+		Expect(result.StatusCode).To(Equal(http.StatusOK))
+		Expect(result.Err).To(MatchError(ContainSubstring("no info object")))
+	})
+
+	It("reports bodies that are not OpenAPI documents", func() {
+		result := fetcher.Fetch(context.Background(), Request{URL: server.URL + "/html"})
+		Expect(result.Err).To(MatchError(ContainSubstring("invalid OpenAPI document")))
+		Expect(result.Reason).To(Equal(ReasonInvalidDocument))
+	})
+
+	It("rejects documents larger than the configured limit", func() {
+		fetcher = NewFetcher(FetcherOptions{MaxBodyBytes: 16})
+		result := fetcher.Fetch(context.Background(), Request{URL: server.URL + "/v2/api-docs"})
+		Expect(result.Err).To(MatchError(ContainSubstring("maximum size")))
+		Expect(result.Reason).To(Equal(ReasonTooLarge))
+	})
+
+	It("classifies timeouts and unreachable endpoints", func() {
+		hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer hanging.Close()
+
+		fetcher = NewFetcher(FetcherOptions{Timeout: 50 * time.Millisecond})
+		Expect(fetcher.Fetch(context.Background(), Request{URL: hanging.URL}).Reason).To(Equal(ReasonTimeout))
+
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		Expect(fetcher.Fetch(context.Background(), Request{URL: closed.URL}).Reason).To(Equal(ReasonUnreachable))
+	})
+
+	It("rejects URLs whose scheme is not http or https", func() {
+		for _, url := range []string{"file:///etc/passwd", "gopher://orders.team-a:70/", "ftp://orders.team-a/openapi.json"} {
+			result := fetcher.Fetch(context.Background(), Request{URL: url})
+			Expect(result.Err).To(MatchError(ContainSubstring("scheme must be http or https")), url)
+			Expect(result.Reason).To(Equal(ReasonInvalidURL), url)
+		}
+	})
+
+	It("follows redirects to the same host only", func() {
+		redirecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/moved" {
+				http.Redirect(w, r, "/v2/api-docs", http.StatusFound)
+				return
+			}
+			if r.URL.Path == "/v2/api-docs" {
+				_, _ = w.Write([]byte(swagger2Doc))
+				return
+			}
+			http.Redirect(w, r, server.URL+"/v2/api-docs", http.StatusFound)
+		}))
+		defer redirecting.Close()
+
+		result := fetcher.Fetch(context.Background(), Request{URL: redirecting.URL + "/moved"})
+		Expect(result.Err).NotTo(HaveOccurred())
+
+		result = fetcher.Fetch(context.Background(), Request{URL: redirecting.URL + "/elsewhere"})
+		Expect(result.Err).To(MatchError(ContainSubstring("redirect to another host")))
+		Expect(result.Document).To(BeNil())
+	})
+
+	It("applies the TLS settings of a request", func() {
+		secure := httptest.NewTLSServer(server.Config.Handler)
+		defer secure.Close()
+
+		By("verifying the certificate by default")
+		result := fetcher.Fetch(context.Background(), Request{URL: secure.URL + "/v2/api-docs"})
+		Expect(result.Reason).To(Equal(ReasonTLS))
+
+		By("skipping verification when requested")
+		result = fetcher.Fetch(context.Background(), Request{URL: secure.URL + "/v2/api-docs", InsecureSkipVerify: true})
+		Expect(result.Err).NotTo(HaveOccurred())
+
+		By("verifying against the requested server name")
+		fetcher = NewFetcher(FetcherOptions{HTTPClient: secure.Client()})
+		result = fetcher.Fetch(context.Background(), Request{URL: secure.URL + "/v2/api-docs", TLSServerName: "example.com"})
+		Expect(result.Err).NotTo(HaveOccurred())
+		result = fetcher.Fetch(context.Background(), Request{URL: secure.URL + "/v2/api-docs", TLSServerName: "orders.example.org"})
+		Expect(result.Reason).To(Equal(ReasonTLS))
+	})
+
+	It("fetches documents concurrently without exceeding the limit", func() {
+		var inFlight, maxInFlight int32
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				observed := atomic.LoadInt32(&maxInFlight)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			_, _ = w.Write([]byte(swagger2Doc))
+		}))
+		defer slow.Close()
+
+		fetcher = NewFetcher(FetcherOptions{MaxConcurrency: 2})
+		reqs := make([]Request, 6)
+		for i := range reqs {
+			reqs[i] = Request{URL: slow.URL}
+		}
+		reqs[3] = Request{URL: server.URL + "/missing"}
+
+		results := fetcher.FetchAll(context.Background(), reqs)
+		Expect(results).To(HaveLen(6))
+		Expect(results[0].Err).NotTo(HaveOccurred())
+		Expect(results[3].StatusCode).To(Equal(http.StatusNotFound))
+		Expect(atomic.LoadInt32(&maxInFlight)).To(BeNumerically("<=", 2))
+	})
+})
@@ -0,0 +1,293 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// HTTPMethods are the operation keys of a path item, in the order they appear in the specifications.
+var HTTPMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// MergeSource is a document contributing to a merged document
+type MergeSource struct {
+	// Key identifies the source in conflicts and namespaces its components
+	Key string
+
+	// PathPrefix is prepended to every path of the source
+	PathPrefix string
+
+	// Document is the OpenAPI 3.x document to merge
+	Document *Document
+}
+
+// MergeOptions configures Merge
+type MergeOptions struct {
+	// Title is the info.title of the merged document
+	Title string
+
+	// Version is the info.version of the merged document
+	Version string
+}
+
+// MergeConflict describes an element of a source that could not be merged as-is
+type MergeConflict struct {
+	// Source is the key of the source the element belongs to
+	Source string
+
+	// Message describes the conflict and how it was resolved
+	Message string
+}
+
+// Merge combines OpenAPI 3.x documents into one. Paths are prefixed per source, components are renamed to
+// "<key>.<name>" with all local references rewritten, and tags and security schemes are merged by name.
+// Sources are merged in key order; on conflicts the element merged first wins and a MergeConflict is reported.
+func Merge(sources []MergeSource, opts MergeOptions) (*Document, []MergeConflict) {
+	sorted := make([]MergeSource, len(sources))
+	copy(sorted, sources)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+
+	m := &merger{
+		paths:           map[string]interface{}{},
+		components:      map[string]interface{}{},
+		securitySchemes: map[string]interface{}{},
+		tagIndex:        map[string]int{},
+		specVersion:     "3.0.3",
+	}
+	for _, source := range sorted {
+		m.add(source)
+	}
+	return m.document(opts), m.conflicts
+}
+
+type merger struct {
+	paths           map[string]interface{}
+	components      map[string]interface{}
+	securitySchemes map[string]interface{}
+	tags            []interface{}
+	tagIndex        map[string]int
+	specVersion     string
+	conflicts       []MergeConflict
+}
+
+func (m *merger) conflict(source MergeSource, format string, args ...interface{}) {
+	m.conflicts = append(m.conflicts, MergeConflict{Source: source.Key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (m *merger) add(source MergeSource) {
+	if source.Document == nil {
+		return
+	}
+	if !source.Document.IsOpenAPI3() {
+		m.conflict(source, "skipped: spec version %s is not OpenAPI 3", source.Document.SpecVersion)
+		return
+	}
+	if strings.HasPrefix(source.Document.SpecVersion, "3.1") {
+		m.specVersion = "3.1.0"
+	}
+
+	content := deepCopyJSON(source.Document.Content).(map[string]interface{})
+	prefix := source.Key + "."
+	rewriteComponentRefs(content, prefix)
+
+	if components, ok := content["components"].(map[string]interface{}); ok {
+		m.addComponents(source, components, prefix)
+	}
+	m.addTags(source, content["tags"])
+
+	paths, _ := content["paths"].(map[string]interface{})
+	security, hasSecurity := content["security"]
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if hasSecurity {
+			// Document-level security would apply to every merged API, so push it down to the operations.
+			for _, method := range HTTPMethods {
+				if op, ok := item[method].(map[string]interface{}); ok {
+					if _, ok := op["security"]; !ok {
+						op["security"] = security
+					}
+				}
+			}
+		}
+
+		merged := joinPath(source.PathPrefix, path)
+		if _, exists := m.paths[merged]; exists {
+			m.conflict(source, "path %s is already defined by another API; skipped", merged)
+			continue
+		}
+		m.paths[merged] = item
+	}
+}
+
+func (m *merger) addComponents(source MergeSource, components map[string]interface{}, prefix string) {
+	for _, section := range sortedKeys(components) {
+		entries, ok := components[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if section == "securitySchemes" {
+			for _, name := range sortedKeys(entries) {
+				existing, exists := m.securitySchemes[name]
+				switch {
+				case !exists:
+					m.securitySchemes[name] = entries[name]
+				case !reflect.DeepEqual(existing, entries[name]):
+					m.conflict(source, "security scheme %q differs from the one already merged; keeping the first definition", name)
+				}
+			}
+			continue
+		}
+
+		merged, _ := m.components[section].(map[string]interface{})
+		if merged == nil {
+			merged = map[string]interface{}{}
+			m.components[section] = merged
+		}
+		for name, value := range entries {
+			merged[prefix+name] = value
+		}
+	}
+}
+
+func (m *merger) addTags(source MergeSource, tags interface{}) {
+	list, _ := tags.([]interface{})
+	for _, tag := range list {
+		tagObj, ok := tag.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := tagObj["name"].(string)
+		if i, exists := m.tagIndex[name]; exists {
+			if !reflect.DeepEqual(m.tags[i], tagObj) {
+				m.conflict(source, "tag %q differs from the one already merged; keeping the first definition", name)
+			}
+			continue
+		}
+		m.tagIndex[name] = len(m.tags)
+		m.tags = append(m.tags, tagObj)
+	}
+}
+
+func (m *merger) document(opts MergeOptions) *Document {
+	content := map[string]interface{}{
+		"openapi": m.specVersion,
+		"info": map[string]interface{}{
+			"title":   opts.Title,
+			"version": opts.Version,
+		},
+		"paths": m.paths,
+	}
+	if len(m.securitySchemes) > 0 {
+		m.components["securitySchemes"] = m.securitySchemes
+	}
+	if len(m.components) > 0 {
+		content["components"] = m.components
+	}
+	if len(m.tags) > 0 {
+		content["tags"] = m.tags
+	}
+	return &Document{
+		Content:     content,
+		SpecVersion: m.specVersion,
+		Title:       opts.Title,
+		Version:     opts.Version,
+	}
+}
+
+// rewriteComponentRefs renames every local component reference, except security schemes, by prefixing its name.
+func rewriteComponentRefs(node interface{}, prefix string) {
+	walkJSON(node, func(obj map[string]interface{}) {
+		ref, ok := obj["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/components/") {
+			return
+		}
+		parts := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 2)
+		if len(parts) != 2 || parts[0] == "securitySchemes" {
+			return
+		}
+		obj["$ref"] = "#/components/" + parts[0] + "/" + prefix + parts[1]
+	})
+}
+
+// joinPath prepends prefix to path, avoiding duplicate or missing slashes.
+func joinPath(prefix, path string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return path
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if path == "/" || path == "" {
+		return prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return prefix + path
+}
+
+// walkJSON calls fn for every object in a decoded JSON tree.
+func walkJSON(node interface{}, fn func(map[string]interface{})) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		fn(v)
+		for _, child := range v {
+			walkJSON(child, fn)
+		}
+	case []interface{}:
+		for _, child := range v {
+			walkJSON(child, fn)
+		}
+	}
+}
+
+// deepCopyJSON copies a decoded JSON tree.
+func deepCopyJSON(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			out[key] = deepCopyJSON(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = deepCopyJSON(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// sortedKeys returns the keys of a JSON object in lexical order.
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
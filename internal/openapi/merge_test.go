@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const petsDoc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "tags": [{"name": "pets"}, {"name": "shared", "description": "from pets"}],
+  "security": [{"bearer": []}],
+  "paths": {
+    "/items": {
+      "get": {
+        "tags": ["pets"],
+        "responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}}}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {"Item": {"type": "object", "properties": {"name": {"type": "string"}}}},
+    "securitySchemes": {"bearer": {"type": "http", "scheme": "bearer"}}
+  }
+}`
+
+const ordersDoc = `{
+  "openapi": "3.1.0",
+  "info": {"title": "Orders", "version": "2.0.0"},
+  "tags": [{"name": "shared", "description": "from orders"}],
+  "paths": {
+    "/items": {
+      "post": {
+        "security": [],
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}}},
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {"Item": {"type": "object", "properties": {"id": {"type": "integer"}}}},
+    "securitySchemes": {"bearer": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}}
+  }
+}`
+
+func mustParse(doc string) *Document {
+	parsed, err := Parse([]byte(doc))
+	Expect(err).NotTo(HaveOccurred())
+	return parsed
+}
+
+var _ = Describe("Merge", func() {
+	var (
+		merged    *Document
+		conflicts []MergeConflict
+	)
+
+	BeforeEach(func() {
+		merged, conflicts = Merge([]MergeSource{
+			{Key: "shop.orders", PathPrefix: "/shop/orders", Document: mustParse(ordersDoc)},
+			{Key: "shop.pets", PathPrefix: "/shop/pets", Document: mustParse(petsDoc)},
+			{Key: "legacy.api", PathPrefix: "/legacy/api", Document: mustParse(swagger2Doc)},
+		}, MergeOptions{Title: "All APIs", Version: "1"})
+	})
+
+	It("prefixes paths per source", func() {
+		paths := merged.Content["paths"].(map[string]interface{})
+		Expect(paths).To(HaveKey("/shop/pets/items"))
+		Expect(paths).To(HaveKey("/shop/orders/items"))
+		Expect(paths).To(HaveLen(2))
+	})
+
+	It("namespaces schemas and rewrites references to them", func() {
+		schemas := merged.Content["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		Expect(schemas).To(HaveKey("shop.pets.Item"))
+		Expect(schemas).To(HaveKey("shop.orders.Item"))
+
+		get := merged.Content["paths"].(map[string]interface{})["/shop/pets/items"].(map[string]interface{})["get"].(map[string]interface{})
+		schema := get["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+		Expect(schema).To(HaveKeyWithValue("$ref", "#/components/schemas/shop.pets.Item"))
+	})
+
+	It("pushes document-level security down to operations", func() {
+		get := merged.Content["paths"].(map[string]interface{})["/shop/pets/items"].(map[string]interface{})["get"].(map[string]interface{})
+		Expect(get).To(HaveKey("security"))
+		Expect(merged.Content).NotTo(HaveKey("security"))
+	})
+
+	It("merges tags and security schemes by name and reports conflicts", func() {
+		Expect(merged.Content["tags"]).To(HaveLen(2))
+		schemes := merged.Content["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})
+		Expect(schemes["bearer"]).To(HaveKeyWithValue("bearerFormat", "JWT"))
+
+		Expect(conflicts).To(ContainElement(MergeConflict{
+			Source:  "shop.pets",
+			Message: `security scheme "bearer" differs from the one already merged; keeping the first definition`,
+		}))
+		Expect(conflicts).To(ContainElement(MergeConflict{
+			Source:  "shop.pets",
+			Message: `tag "shared" differs from the one already merged; keeping the first definition`,
+		}))
+	})
+
+	It("skips documents that are not OpenAPI 3 and uses the highest minor version", func() {
+		Expect(conflicts).To(ContainElement(MergeConflict{Source: "legacy.api", Message: "skipped: spec version 2.0 is not OpenAPI 3"}))
+		Expect(merged.SpecVersion).To(Equal("3.1.0"))
+		Expect(merged.Title).To(Equal("All APIs"))
+	})
+
+	It("reports colliding paths", func() {
+		_, conflicts := Merge([]MergeSource{
+			{Key: "a.one", PathPrefix: "/api", Document: mustParse(petsDoc)},
+			{Key: "b.two", PathPrefix: "/api", Document: mustParse(petsDoc)},
+		}, MergeOptions{})
+		Expect(conflicts).To(ConsistOf(MergeConflict{Source: "b.two", Message: "path /api/items is already defined by another API; skipped"}))
+	})
+})
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"strings"
+)
+
+// PruneOperations removes every operation whose HTTP method is not in allowed, and path items left
+// without operations. Components that were referenced before and are no longer referenced afterwards
+// are removed as well; components that were never referenced are kept. It returns the number of
+// operations removed. An empty allowed list allows every method.
+func PruneOperations(doc *Document, allowed []string) int {
+	if len(allowed) == 0 {
+		return 0
+	}
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, method := range allowed {
+		allowedSet[strings.ToLower(method)] = true
+	}
+
+	reachableBefore := reachableComponents(doc)
+
+	removed := 0
+	paths, _ := doc.Content["paths"].(map[string]interface{})
+	for path, item := range paths {
+		itemObj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		remaining := 0
+		for _, method := range HTTPMethods {
+			if _, ok := itemObj[method]; !ok {
+				continue
+			}
+			if allowedSet[method] {
+				remaining++
+				continue
+			}
+			delete(itemObj, method)
+			removed++
+		}
+		if remaining == 0 {
+			if _, isRef := itemObj["$ref"]; !isRef {
+				delete(paths, path)
+			}
+		}
+	}
+	if removed == 0 {
+		return 0
+	}
+
+	reachableAfter := reachableComponents(doc)
+	for pointer := range reachableBefore {
+		if !reachableAfter[pointer] {
+			removeComponent(doc, pointer)
+		}
+	}
+	return removed
+}
+
+// componentContainers returns the objects holding reusable components, keyed by their JSON pointer prefix.
+// Security schemes are excluded since they are referenced by name rather than by $ref.
+func componentContainers(doc *Document) map[string]map[string]interface{} {
+	containers := map[string]map[string]interface{}{}
+	if doc.IsSwagger2() {
+		for _, section := range []string{"definitions", "parameters", "responses"} {
+			if obj, ok := doc.Content[section].(map[string]interface{}); ok {
+				containers["#/"+section+"/"] = obj
+			}
+		}
+		return containers
+	}
+
+	components, _ := doc.Content["components"].(map[string]interface{})
+	for section, value := range components {
+		if obj, ok := value.(map[string]interface{}); ok && section != "securitySchemes" {
+			containers["#/components/"+section+"/"] = obj
+		}
+	}
+	return containers
+}
+
+// reachableComponents returns the JSON pointers of all components referenced, directly or through
+// other components, from outside the component sections.
+func reachableComponents(doc *Document) map[string]bool {
+	containers := componentContainers(doc)
+	// lookup resolves a reference to the component containing it, returning the component's own pointer.
+	lookup := func(ref string) (string, interface{}, bool) {
+		for prefix, container := range containers {
+			if strings.HasPrefix(ref, prefix) {
+				name := strings.SplitN(strings.TrimPrefix(ref, prefix), "/", 2)[0]
+				value, ok := container[unescapePointer(name)]
+				return prefix + name, value, ok
+			}
+		}
+		return "", nil, false
+	}
+
+	// Roots are the references found outside the component sections.
+	roots := make(map[string]interface{}, len(doc.Content))
+	for key, value := range doc.Content {
+		roots[key] = value
+	}
+	if doc.IsSwagger2() {
+		delete(roots, "definitions")
+		delete(roots, "parameters")
+		delete(roots, "responses")
+	} else if components, ok := roots["components"].(map[string]interface{}); ok {
+		roots["components"] = map[string]interface{}{"securitySchemes": components["securitySchemes"]}
+	}
+
+	reachable := map[string]bool{}
+	queue := collectRefs(roots)
+	for len(queue) > 0 {
+		pointer, value, ok := lookup(queue[0])
+		queue = queue[1:]
+		if !ok || reachable[pointer] {
+			continue
+		}
+		reachable[pointer] = true
+		queue = append(queue, collectRefs(value)...)
+	}
+	return reachable
+}
+
+// removeComponent deletes the component at a local JSON pointer.
+func removeComponent(doc *Document, pointer string) {
+	for prefix, container := range componentContainers(doc) {
+		if strings.HasPrefix(pointer, prefix) {
+			delete(container, unescapePointer(strings.TrimPrefix(pointer, prefix)))
+			return
+		}
+	}
+}
+
+// collectRefs returns every local $ref found in a decoded JSON tree.
+func collectRefs(node interface{}) []string {
+	var refs []string
+	walkJSON(node, func(obj map[string]interface{}) {
+		if ref, ok := obj["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+			refs = append(refs, ref)
+		}
+	})
+	return refs
+}
+
+// unescapePointer decodes a JSON pointer reference token.
+func unescapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
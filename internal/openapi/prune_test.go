@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const crudDoc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Accounts", "version": "1"},
+  "paths": {
+    "/accounts": {
+      "get": {"responses": {"200": {"$ref": "#/components/responses/AccountList"}}},
+      "post": {"requestBody": {"$ref": "#/components/requestBodies/NewAccount"}, "responses": {"201": {"description": "created"}}}
+    },
+    "/accounts/{id}": {
+      "delete": {"responses": {"204": {"description": "deleted"}}}
+    }
+  },
+  "components": {
+    "schemas": {
+      "Account": {"type": "object", "properties": {"owner": {"$ref": "#/components/schemas/Owner"}}},
+      "Owner": {"type": "string"},
+      "NewAccount": {"type": "object", "properties": {"owner": {"$ref": "#/components/schemas/Owner"}, "secret": {"$ref": "#/components/schemas/Secret"}}},
+      "Secret": {"type": "string"},
+      "Unused": {"type": "string"}
+    },
+    "responses": {
+      "AccountList": {"description": "ok", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Account"}}}}}
+    },
+    "requestBodies": {
+      "NewAccount": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/NewAccount"}}}}
+    }
+  }
+}`
+
+var _ = Describe("PruneOperations", func() {
+	It("removes disallowed operations and the components only they used", func() {
+		doc := mustParse(crudDoc)
+		Expect(PruneOperations(doc, []string{"get"})).To(Equal(2))
+
+		paths := doc.Content["paths"].(map[string]interface{})
+		Expect(paths).To(HaveLen(1))
+		Expect(paths["/accounts"]).To(HaveKey("get"))
+		Expect(paths["/accounts"]).NotTo(HaveKey("post"))
+
+		components := doc.Content["components"].(map[string]interface{})
+		schemas := components["schemas"].(map[string]interface{})
+		Expect(schemas).To(HaveKey("Account"))
+		Expect(schemas).To(HaveKey("Owner"))
+		Expect(schemas).NotTo(HaveKey("NewAccount"))
+		Expect(schemas).NotTo(HaveKey("Secret"))
+		Expect(schemas).To(HaveKey("Unused"), "components that were never referenced are kept")
+		Expect(components["requestBodies"]).To(BeEmpty())
+		Expect(components["responses"]).To(HaveKey("AccountList"))
+	})
+
+	It("prunes Swagger 2.0 definitions", func() {
+		doc := mustParse(springDoc)
+		Expect(PruneOperations(doc, []string{"get"})).To(Equal(2))
+		Expect(doc.Content["paths"]).To(HaveLen(1))
+		Expect(doc.Content["definitions"]).To(HaveKey("Invoice"))
+	})
+
+	It("leaves the document untouched when every method is allowed", func() {
+		doc := mustParse(crudDoc)
+		Expect(PruneOperations(doc, nil)).To(Equal(0))
+		Expect(PruneOperations(doc, []string{"GET", "post", "delete"})).To(Equal(0))
+		Expect(doc.Content["paths"]).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// RewriteServers points the document at baseURL, keeping the path of the servers it declares.
+// For OpenAPI 3 every servers list (document, path and operation level) is rewritten;
+// for Swagger 2.0 host, schemes and basePath are set.
+func RewriteServers(doc *Document, baseURL string) error {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid server base URL %q: %w", baseURL, err)
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return fmt.Errorf("server base URL %q must be absolute", baseURL)
+	}
+
+	if doc.IsSwagger2() {
+		basePath, _ := doc.Content["basePath"].(string)
+		doc.Content["host"] = base.Host
+		doc.Content["schemes"] = []interface{}{base.Scheme}
+		doc.Content["basePath"] = joinURLPath(base.Path, basePath)
+		return nil
+	}
+
+	servers, _ := doc.Content["servers"].([]interface{})
+	if len(servers) == 0 {
+		doc.Content["servers"] = []interface{}{map[string]interface{}{"url": strings.TrimSuffix(base.String(), "/")}}
+	} else {
+		rewriteServerList(servers, base)
+	}
+
+	paths, _ := doc.Content["paths"].(map[string]interface{})
+	for _, item := range paths {
+		itemObj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if servers, ok := itemObj["servers"].([]interface{}); ok {
+			rewriteServerList(servers, base)
+		}
+		for _, method := range HTTPMethods {
+			if op, ok := itemObj[method].(map[string]interface{}); ok {
+				if servers, ok := op["servers"].([]interface{}); ok {
+					rewriteServerList(servers, base)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// rewriteServerList rewrites server objects in place, dropping variables since the new URL is concrete.
+func rewriteServerList(servers []interface{}, base *url.URL) {
+	for _, server := range servers {
+		serverObj, ok := server.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		original, _ := serverObj["url"].(string)
+		path := serverPath(resolveServerVariables(original, serverObj["variables"]))
+
+		rewritten := *base
+		rewritten.Path = joinURLPath(base.Path, path)
+		serverObj["url"] = strings.TrimSuffix(rewritten.String(), "/")
+		delete(serverObj, "variables")
+	}
+}
+
+// resolveServerVariables substitutes the default value of each server variable into a server URL.
+func resolveServerVariables(serverURL string, variables interface{}) string {
+	vars, _ := variables.(map[string]interface{})
+	for name, variable := range vars {
+		varObj, _ := variable.(map[string]interface{})
+		if value, ok := varObj["default"].(string); ok {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", value)
+		}
+	}
+	return serverURL
+}
+
+// serverPath returns the path component of an absolute or relative server URL.
+func serverPath(serverURL string) string {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Path
+}
+
+// joinURLPath joins two URL paths with a single slash, returning "/" for two empty paths.
+func joinURLPath(base, path string) string {
+	joined := strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+	if joined != "/" {
+		joined = strings.TrimSuffix(joined, "/")
+	}
+	return joined
+}
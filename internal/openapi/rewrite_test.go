@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RewriteServers", func() {
+	It("rewrites OpenAPI 3 servers and keeps their paths", func() {
+		doc := mustParse(`{
+		  "openapi": "3.0.3",
+		  "info": {"title": "t", "version": "v"},
+		  "servers": [
+		    {"url": "http://localhost:8080/api/v1"},
+		    {"url": "{scheme}://internal.example/{base}", "variables": {"scheme": {"default": "https"}, "base": {"default": "v2"}}}
+		  ],
+		  "paths": {"/a": {"servers": [{"url": "/raw"}], "get": {"servers": [{"url": "http://other/op"}], "responses": {}}}}
+		}`)
+		Expect(RewriteServers(doc, "http://orders.shop.svc.cluster.local:8080")).To(Succeed())
+
+		Expect(doc.Content["servers"]).To(Equal([]interface{}{
+			map[string]interface{}{"url": "http://orders.shop.svc.cluster.local:8080/api/v1"},
+			map[string]interface{}{"url": "http://orders.shop.svc.cluster.local:8080/v2"},
+		}))
+		item := doc.Content["paths"].(map[string]interface{})["/a"].(map[string]interface{})
+		Expect(item["servers"]).To(ConsistOf(map[string]interface{}{"url": "http://orders.shop.svc.cluster.local:8080/raw"}))
+		op := item["get"].(map[string]interface{})
+		Expect(op["servers"]).To(ConsistOf(map[string]interface{}{"url": "http://orders.shop.svc.cluster.local:8080/op"}))
+	})
+
+	It("adds a server to OpenAPI 3 documents without one", func() {
+		doc := mustParse(openAPI3Doc)
+		Expect(RewriteServers(doc, "https://api.example.com/shop/orders/")).To(Succeed())
+		Expect(doc.Content["servers"]).To(ConsistOf(map[string]interface{}{"url": "https://api.example.com/shop/orders"}))
+	})
+
+	It("sets host, schemes and basePath of Swagger 2.0 documents", func() {
+		doc := mustParse(swagger2Doc)
+		Expect(RewriteServers(doc, "https://api.example.com/pets")).To(Succeed())
+		Expect(doc.Content).To(HaveKeyWithValue("host", "api.example.com"))
+		Expect(doc.Content).To(HaveKeyWithValue("schemes", ConsistOf("https")))
+		Expect(doc.Content).To(HaveKeyWithValue("basePath", "/pets/api"))
+	})
+
+	It("rejects relative base URLs", func() {
+		Expect(RewriteServers(mustParse(openAPI3Doc), "/relative")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,288 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	"github.com/hellices/openapi-aggregator-operator/internal/oci"
+)
+
+// minResyncInterval mirrors the lower bound the controller applies to spec.resyncInterval.
+const minResyncInterval = 10 * time.Second
+
+// openapiaggregatorlog is for logging in this package.
+var openapiaggregatorlog = logf.Log.WithName("openapiaggregator-resource")
+
+// SetupOpenAPIAggregatorWebhookWithManager registers the webhook for OpenAPIAggregator in the manager.
+func SetupOpenAPIAggregatorWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&observabilityv1alpha1.OpenAPIAggregator{}).
+		WithValidator(&OpenAPIAggregatorCustomValidator{}).
+		WithDefaulter(&OpenAPIAggregatorCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-observability-aggregator-io-v1alpha1-openapiaggregator,mutating=true,failurePolicy=fail,sideEffects=None,groups=observability.aggregator.io,resources=openapiaggregators,verbs=create;update,versions=v1alpha1,name=mopenapiaggregator-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// OpenAPIAggregatorCustomDefaulter sets default values on OpenAPIAggregator resources when they are
+// created or updated, including fields explicitly set to an empty string, which CRD defaults skip.
+type OpenAPIAggregatorCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &OpenAPIAggregatorCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter.
+func (d *OpenAPIAggregatorCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	openapiaggregator, ok := obj.(*observabilityv1alpha1.OpenAPIAggregator)
+	if !ok {
+		return fmt.Errorf("expected an OpenAPIAggregator object but got %T", obj)
+	}
+	openapiaggregatorlog.V(1).Info("Defaulting for OpenAPIAggregator", "name", openapiaggregator.GetName())
+
+	spec := &openapiaggregator.Spec
+	setDefault(&spec.DefaultPath, "/v2/api-docs")
+	setDefault(&spec.DefaultPort, "8080")
+	setDefault(&spec.SwaggerAnnotation, "openapi.aggregator.io/swagger")
+	setDefault(&spec.PathAnnotation, "openapi.aggregator.io/path")
+	setDefault(&spec.PortAnnotation, "openapi.aggregator.io/port")
+	setDefault(&spec.AllowedMethodsAnnotation, "openapi.aggregator.io/allowed-methods")
+	setDefault(&spec.SchemeAnnotation, "openapi.aggregator.io/scheme")
+	setDefault(&spec.URLAnnotation, "openapi.aggregator.io/url")
+	setDefault(&spec.TLSServerNameAnnotation, "openapi.aggregator.io/tls-server-name")
+	setDefault(&spec.InsecureSkipVerifyAnnotation, "openapi.aggregator.io/insecure-skip-verify")
+	for i, namespace := range spec.WatchNamespaces {
+		spec.WatchNamespaces[i] = strings.TrimSpace(namespace)
+	}
+	if merged := spec.Output.Merged; merged != nil {
+		setDefault(&merged.Title, "Aggregated API")
+		setDefault(&merged.Version, "1.0.0")
+		setDefault(&merged.PathPrefix, "/{namespace}/{name}")
+	}
+	if ociSpec := spec.Output.OCI; ociSpec != nil {
+		setDefault(&ociSpec.TagTemplate, "{digest}")
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-observability-aggregator-io-v1alpha1-openapiaggregator,mutating=false,failurePolicy=fail,sideEffects=None,groups=observability.aggregator.io,resources=openapiaggregators,verbs=create;update,versions=v1alpha1,name=vopenapiaggregator-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// OpenAPIAggregatorCustomValidator validates OpenAPIAggregator resources when they are created or updated.
+type OpenAPIAggregatorCustomValidator struct{}
+
+var _ webhook.CustomValidator = &OpenAPIAggregatorCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *OpenAPIAggregatorCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	openapiaggregator, ok := obj.(*observabilityv1alpha1.OpenAPIAggregator)
+	if !ok {
+		return nil, fmt.Errorf("expected an OpenAPIAggregator object but got %T", obj)
+	}
+	openapiaggregatorlog.V(1).Info("Validation for OpenAPIAggregator upon creation", "name", openapiaggregator.GetName())
+	return validateOpenAPIAggregator(openapiaggregator)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *OpenAPIAggregatorCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	openapiaggregator, ok := newObj.(*observabilityv1alpha1.OpenAPIAggregator)
+	if !ok {
+		return nil, fmt.Errorf("expected an OpenAPIAggregator object for the newObj but got %T", newObj)
+	}
+	openapiaggregatorlog.V(1).Info("Validation for OpenAPIAggregator upon update", "name", openapiaggregator.GetName())
+	return validateOpenAPIAggregator(openapiaggregator)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletion is always allowed.
+func (v *OpenAPIAggregatorCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateOpenAPIAggregator returns the field errors of an OpenAPIAggregator as an Invalid error,
+// and warnings for settings that are accepted but do not behave as written.
+func validateOpenAPIAggregator(openapiaggregator *observabilityv1alpha1.OpenAPIAggregator) (admission.Warnings, error) {
+	spec := &openapiaggregator.Spec
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validatePort(spec.DefaultPort, specPath.Child("defaultPort"))...)
+	allErrs = append(allErrs, validateURLPath(spec.DefaultPath, specPath.Child("defaultPath"))...)
+	allErrs = append(allErrs, validateWatchNamespaces(spec.WatchNamespaces, specPath.Child("watchNamespaces"))...)
+	allErrs = append(allErrs, validateAnnotationKey(spec.SwaggerAnnotation, specPath.Child("swaggerAnnotation"))...)
+	allErrs = append(allErrs, validateAnnotationKey(spec.PathAnnotation, specPath.Child("pathAnnotation"))...)
+	allErrs = append(allErrs, validateAnnotationKey(spec.PortAnnotation, specPath.Child("portAnnotation"))...)
+	allErrs = append(allErrs, validateAnnotationKey(spec.AllowedMethodsAnnotation, specPath.Child("allowedMethodsAnnotation"))...)
+	allErrs = append(allErrs, validateAnnotationKey(spec.SchemeAnnotation, specPath.Child("schemeAnnotation"))...)
+	allErrs = append(allErrs, validateAnnotationKey(spec.URLAnnotation, specPath.Child("urlAnnotation"))...)
+	allErrs = append(allErrs, validateAnnotationKey(spec.TLSServerNameAnnotation, specPath.Child("tlsServerNameAnnotation"))...)
+	allErrs = append(allErrs, validateAnnotationKey(spec.InsecureSkipVerifyAnnotation, specPath.Child("insecureSkipVerifyAnnotation"))...)
+	allErrs = append(allErrs, metav1validation.ValidateLabels(spec.LabelSelector, specPath.Child("labelSelector"))...)
+	for i, requirement := range spec.LabelSelectorExpressions {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelectorRequirement(requirement,
+			metav1validation.LabelSelectorValidationOptions{}, specPath.Child("labelSelectorExpressions").Index(i))...)
+	}
+	if spec.ServerRewrite != nil && spec.ServerRewrite.URLTemplate != "" {
+		allErrs = append(allErrs, validateURLTemplate(spec.ServerRewrite.URLTemplate, specPath.Child("serverRewrite", "urlTemplate"))...)
+	}
+	allErrs = append(allErrs, validateOutput(&spec.Output, specPath.Child("output"))...)
+
+	var warnings admission.Warnings
+	if spec.ResyncInterval != nil && spec.ResyncInterval.Duration < minResyncInterval {
+		warnings = append(warnings, fmt.Sprintf("spec.resyncInterval %s is below the minimum and is raised to %s",
+			spec.ResyncInterval.Duration, minResyncInterval))
+	}
+	if spec.Output.CompressSpecs && !spec.Output.StoreSpecs && spec.Output.Merged == nil {
+		warnings = append(warnings, "spec.output.compressSpecs has no effect without storeSpecs or merged")
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(observabilityv1alpha1.GroupVersion.WithKind("OpenAPIAggregator").GroupKind(),
+		openapiaggregator.Name, allErrs)
+}
+
+// validateOutput validates the output settings of an OpenAPIAggregator.
+func validateOutput(output *observabilityv1alpha1.OutputSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if output.ConfigMapName != "" {
+		allErrs = append(allErrs, validateObjectName(output.ConfigMapName, fldPath.Child("configMapName"))...)
+	}
+	if output.Merged != nil && output.Merged.PathPrefix != "" && !strings.HasPrefix(output.Merged.PathPrefix, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("merged", "pathPrefix"), output.Merged.PathPrefix, "must start with '/'"))
+	}
+	if ociSpec := output.OCI; ociSpec != nil {
+		ociPath := fldPath.Child("oci")
+		if _, _, err := oci.ParseRepository(ociSpec.Repository); err != nil {
+			allErrs = append(allErrs, field.Invalid(ociPath.Child("repository"), ociSpec.Repository, err.Error()))
+		}
+		sampleTag := strings.NewReplacer("{digest}", "0123456789ab", "{timestamp}", "20060102150405", "{generation}", "1").
+			Replace(ociSpec.TagTemplate)
+		if ociSpec.TagTemplate != "" && !oci.ValidTag(sampleTag) {
+			allErrs = append(allErrs, field.Invalid(ociPath.Child("tagTemplate"), ociSpec.TagTemplate,
+				"must produce a valid tag: letters, digits, '_', '.' and '-', at most 128 characters, not starting with '.' or '-'"))
+		}
+		if ociSpec.CredentialsSecret != "" {
+			allErrs = append(allErrs, validateObjectName(ociSpec.CredentialsSecret, ociPath.Child("credentialsSecret"))...)
+		}
+	}
+	return allErrs
+}
+
+// validatePort checks that a port is a number between 1 and 65535 or a valid Service port name.
+func validatePort(port string, fldPath *field.Path) field.ErrorList {
+	if port == "" {
+		return nil
+	}
+	var msgs []string
+	if number, err := strconv.Atoi(port); err == nil {
+		msgs = validation.IsValidPortNum(number)
+	} else {
+		msgs = validation.IsValidPortName(port)
+	}
+	var allErrs field.ErrorList
+	for _, msg := range msgs {
+		allErrs = append(allErrs, field.Invalid(fldPath, port, msg))
+	}
+	return allErrs
+}
+
+// validateURLPath checks that a path is an absolute URL path.
+func validateURLPath(path string, fldPath *field.Path) field.ErrorList {
+	if path == "" {
+		return nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return field.ErrorList{field.Invalid(fldPath, path, "must start with '/'")}
+	}
+	if strings.ContainsAny(path, " \t\r\n") {
+		return field.ErrorList{field.Invalid(fldPath, path, "must not contain whitespace")}
+	}
+	if parsed, err := url.Parse(path); err != nil || parsed.Host != "" {
+		return field.ErrorList{field.Invalid(fldPath, path, "must be a URL path")}
+	}
+	return nil
+}
+
+// validateWatchNamespaces checks that each watched namespace is a namespace name, or "" or "*" for all namespaces.
+func validateWatchNamespaces(namespaces []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, namespace := range namespaces {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || namespace == "*" {
+			continue
+		}
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), namespace, msg))
+		}
+	}
+	return allErrs
+}
+
+// validateAnnotationKey checks that a key can be used as an annotation key.
+func validateAnnotationKey(key string, fldPath *field.Path) field.ErrorList {
+	if key == "" {
+		return nil
+	}
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsQualifiedName(key) {
+		allErrs = append(allErrs, field.Invalid(fldPath, key, msg))
+	}
+	return allErrs
+}
+
+// validateObjectName checks that a name can be used as the name of a ConfigMap or Secret.
+func validateObjectName(name string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsDNS1123Subdomain(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+	return allErrs
+}
+
+// validateURLTemplate checks that a server URL template yields an absolute HTTP(S) URL.
+func validateURLTemplate(template string, fldPath *field.Path) field.ErrorList {
+	sample := strings.NewReplacer("{namespace}", "default", "{name}", "service", "{port}", "8080").Replace(template)
+	parsed, err := url.Parse(sample)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, template, err.Error())}
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, template, "must be an absolute http or https URL")}
+	}
+	return nil
+}
+
+// setDefault sets value to defaultValue if it is empty.
+func setDefault(value *string, defaultValue string) {
+	if *value == "" {
+		*value = defaultValue
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	observabilityv1beta1 "github.com/hellices/openapi-aggregator-operator/api/v1beta1"
+)
+
+// causeFields returns the fields of the causes of an Invalid error.
+func causeFields(err error) []string {
+	status, ok := err.(apierrors.APIStatus)
+	Expect(ok).To(BeTrue())
+	Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	var fields []string
+	for _, cause := range status.Status().Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	return fields
+}
+
+var _ = Describe("OpenAPIAggregator Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *observabilityv1alpha1.OpenAPIAggregator
+		validator OpenAPIAggregatorCustomValidator
+		defaulter OpenAPIAggregatorCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &observabilityv1alpha1.OpenAPIAggregator{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		}
+		validator = OpenAPIAggregatorCustomValidator{}
+		defaulter = OpenAPIAggregatorCustomDefaulter{}
+	})
+
+	Context("When defaulting", func() {
+		It("Should fill empty fields", func() {
+			obj.Spec.Output.Merged = &observabilityv1alpha1.MergedOutputSpec{}
+			obj.Spec.Output.OCI = &observabilityv1alpha1.OCIOutputSpec{Repository: "registry.example.com/specs"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.DefaultPath).To(Equal("/v2/api-docs"))
+			Expect(obj.Spec.DefaultPort).To(Equal("8080"))
+			Expect(obj.Spec.SwaggerAnnotation).To(Equal("openapi.aggregator.io/swagger"))
+			Expect(obj.Spec.AllowedMethodsAnnotation).To(Equal("openapi.aggregator.io/allowed-methods"))
+			Expect(obj.Spec.URLAnnotation).To(Equal("openapi.aggregator.io/url"))
+			Expect(obj.Spec.Output.Merged.PathPrefix).To(Equal("/{namespace}/{name}"))
+			Expect(obj.Spec.Output.OCI.TagTemplate).To(Equal("{digest}"))
+		})
+
+		It("Should keep fields that are set", func() {
+			obj.Spec.DefaultPath = "/openapi.json"
+			obj.Spec.WatchNamespaces = []string{" team-a "}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.DefaultPath).To(Equal("/openapi.json"))
+			Expect(obj.Spec.WatchNamespaces).To(Equal([]string{"team-a"}))
+		})
+	})
+
+	Context("When validating", func() {
+		BeforeEach(func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+		})
+
+		It("Should admit the defaults", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject malformed ports, paths, namespaces and annotation keys", func() {
+			obj.Spec.DefaultPort = "http_api"
+			obj.Spec.DefaultPath = "v2/api-docs"
+			obj.Spec.WatchNamespaces = []string{"team-a", "Team_B", "*"}
+			obj.Spec.PortAnnotation = "not a key"
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(Equal([]string{
+				"spec.defaultPort",
+				"spec.defaultPath",
+				"spec.watchNamespaces[1]",
+				"spec.portAnnotation",
+			}))
+		})
+
+		It("Should admit named ports", func() {
+			obj.Spec.DefaultPort = "http-api"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject port names longer than 15 characters", func() {
+			obj.Spec.DefaultPort = "openapi-documents"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(Equal([]string{"spec.defaultPort"}))
+		})
+
+		It("Should reject ports out of range", func() {
+			obj.Spec.DefaultPort = "70000"
+			_, err := validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)
+			Expect(causeFields(err)).To(Equal([]string{"spec.defaultPort"}))
+		})
+
+		It("Should reject invalid output settings", func() {
+			obj.Spec.LabelSelector = map[string]string{"app": "bad value"}
+			obj.Spec.ServerRewrite = &observabilityv1alpha1.ServerRewriteSpec{URLTemplate: "{name}.{namespace}.svc"}
+			obj.Spec.Output.ConfigMapName = "Specs"
+			obj.Spec.Output.Merged = &observabilityv1alpha1.MergedOutputSpec{PathPrefix: "{namespace}"}
+			obj.Spec.Output.OCI = &observabilityv1alpha1.OCIOutputSpec{
+				Repository:  "registry.example.com/Specs",
+				TagTemplate: "-{digest}",
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(Equal([]string{
+				"spec.labelSelector",
+				"spec.serverRewrite.urlTemplate",
+				"spec.output.configMapName",
+				"spec.output.merged.pathPrefix",
+				"spec.output.oci.repository",
+				"spec.output.oci.tagTemplate",
+			}))
+		})
+
+		It("Should admit templated server URLs", func() {
+			obj.Spec.ServerRewrite = &observabilityv1alpha1.ServerRewriteSpec{URLTemplate: "http://{name}.{namespace}.svc:{port}"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should warn about settings that do not apply as written", func() {
+			obj.Spec.ResyncInterval = &metav1.Duration{Duration: time.Second}
+			obj.Spec.Output.CompressSpecs = true
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(2))
+		})
+
+		It("Should always admit deletion", func() {
+			obj.Spec.DefaultPort = "http"
+			_, err := validator.ValidateDelete(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When converting", func() {
+		It("Should convert to v1beta1 with typed fields", func() {
+			obj.Spec.DefaultPort = "9090"
+			obj.Status.CollectedAPIs = []observabilityv1alpha1.APIInfo{
+				{Name: "pets", LastUpdated: "2025-01-02T03:04:05Z"},
+				{Name: "orders", Error: "connection refused"},
+			}
+
+			hub := &observabilityv1beta1.OpenAPIAggregator{}
+			Expect(obj.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.DefaultPort).To(Equal(int32(9090)))
+			Expect(hub.Status.CollectedAPIs[0].LastUpdated.Time).To(Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+			Expect(hub.Status.CollectedAPIs[1].LastUpdated).To(BeNil())
+			Expect(hub.Annotations).To(BeEmpty())
+
+			converted := &observabilityv1alpha1.OpenAPIAggregator{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted).To(Equal(obj))
+		})
+
+		It("Should convert the label map and expressions to a v1beta1 label selector", func() {
+			obj.Spec.LabelSelector = map[string]string{"app": "orders"}
+			obj.Spec.LabelSelectorExpressions = []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"payments"}},
+			}
+
+			hub := &observabilityv1beta1.OpenAPIAggregator{}
+			Expect(obj.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.LabelSelector).To(Equal(&metav1.LabelSelector{
+				MatchLabels:      obj.Spec.LabelSelector,
+				MatchExpressions: obj.Spec.LabelSelectorExpressions,
+			}))
+
+			converted := &observabilityv1alpha1.OpenAPIAggregator{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted).To(Equal(obj))
+
+			By("leaving the v1beta1 label selector unset without labels or expressions")
+			obj.Spec.LabelSelector = nil
+			obj.Spec.LabelSelectorExpressions = nil
+			Expect(obj.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.LabelSelector).To(BeNil())
+		})
+
+		It("Should reject invalid label selector expressions", func() {
+			obj.Spec.LabelSelectorExpressions = []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: metav1.LabelSelectorOpIn},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(Equal([]string{"spec.labelSelectorExpressions[0].values"}))
+		})
+
+		It("Should keep a port that v1beta1 cannot represent", func() {
+			obj.Spec.DefaultPort = "http"
+
+			hub := &observabilityv1beta1.OpenAPIAggregator{}
+			Expect(obj.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.DefaultPort).To(BeZero())
+			Expect(hub.Annotations).To(HaveKey("observability.aggregator.io/v1alpha1-values"))
+
+			converted := &observabilityv1alpha1.OpenAPIAggregator{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted.Spec.DefaultPort).To(Equal("http"))
+			Expect(converted.Annotations).To(BeEmpty())
+
+			By("changing the port through v1beta1")
+			hub.Spec.DefaultPort = 8443
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted.Spec.DefaultPort).To(Equal("8443"))
+		})
+	})
+})
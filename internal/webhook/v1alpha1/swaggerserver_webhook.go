@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+)
+
+// swaggerserverlog is for logging in this package.
+var swaggerserverlog = logf.Log.WithName("swaggerserver-resource")
+
+// SetupSwaggerServerWebhookWithManager registers the webhook for SwaggerServer in the manager.
+func SetupSwaggerServerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&observabilityv1alpha1.SwaggerServer{}).
+		WithValidator(&SwaggerServerCustomValidator{}).
+		WithDefaulter(&SwaggerServerCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-observability-aggregator-io-v1alpha1-swaggerserver,mutating=true,failurePolicy=fail,sideEffects=None,groups=observability.aggregator.io,resources=swaggerservers,verbs=create;update,versions=v1alpha1,name=mswaggerserver-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SwaggerServerCustomDefaulter sets default values on SwaggerServer resources when they are created or updated,
+// so that the defaults the controller applies are visible on the resource.
+type SwaggerServerCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &SwaggerServerCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter.
+func (d *SwaggerServerCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	swaggerserver, ok := obj.(*observabilityv1alpha1.SwaggerServer)
+	if !ok {
+		return fmt.Errorf("expected a SwaggerServer object but got %T", obj)
+	}
+	swaggerserverlog.V(1).Info("Defaulting for SwaggerServer", "name", swaggerserver.GetName())
+
+	spec := &swaggerserver.Spec
+	setDefault(&spec.Image, "ghcr.io/hellices/openapi-multi-swagger:latest")
+	setDefault(&spec.ImagePullPolicy, "IfNotPresent")
+	setDefault(&spec.WatchIntervalSeconds, "10")
+	setDefault(&spec.LogLevel, "info")
+	setDefault(&spec.DevMode, "false")
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-observability-aggregator-io-v1alpha1-swaggerserver,mutating=false,failurePolicy=fail,sideEffects=None,groups=observability.aggregator.io,resources=swaggerservers,verbs=create;update,versions=v1alpha1,name=vswaggerserver-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SwaggerServerCustomValidator validates SwaggerServer resources when they are created or updated.
+type SwaggerServerCustomValidator struct{}
+
+var _ webhook.CustomValidator = &SwaggerServerCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *SwaggerServerCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	swaggerserver, ok := obj.(*observabilityv1alpha1.SwaggerServer)
+	if !ok {
+		return nil, fmt.Errorf("expected a SwaggerServer object but got %T", obj)
+	}
+	swaggerserverlog.V(1).Info("Validation for SwaggerServer upon creation", "name", swaggerserver.GetName())
+	return nil, validateSwaggerServer(swaggerserver)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *SwaggerServerCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	swaggerserver, ok := newObj.(*observabilityv1alpha1.SwaggerServer)
+	if !ok {
+		return nil, fmt.Errorf("expected a SwaggerServer object for the newObj but got %T", newObj)
+	}
+	swaggerserverlog.V(1).Info("Validation for SwaggerServer upon update", "name", swaggerserver.GetName())
+	return nil, validateSwaggerServer(swaggerserver)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletion is always allowed.
+func (v *SwaggerServerCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateSwaggerServer returns the field errors of a SwaggerServer as an Invalid error.
+func validateSwaggerServer(swaggerserver *observabilityv1alpha1.SwaggerServer) error {
+	spec := &swaggerserver.Spec
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateObjectName(spec.ConfigMapName, specPath.Child("configMapName"))...)
+	for _, msg := range validation.IsValidPortNum(int(spec.Port)) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("port"), spec.Port, msg))
+	}
+	if spec.WatchIntervalSeconds != "" {
+		if seconds, err := strconv.Atoi(spec.WatchIntervalSeconds); err != nil || seconds < 1 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("watchIntervalSeconds"), spec.WatchIntervalSeconds,
+				"must be a positive number of seconds"))
+		}
+	}
+	allErrs = append(allErrs, validateResources(spec.Resources, specPath.Child("resources"))...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(observabilityv1alpha1.GroupVersion.WithKind("SwaggerServer").GroupKind(),
+		swaggerserver.Name, allErrs)
+}
+
+// validateResources checks that resource names and quantities parse and that no request exceeds its limit.
+func validateResources(resources observabilityv1alpha1.ResourceRequirements, fldPath *field.Path) field.ErrorList {
+	limits, allErrs := validateResourceList(resources.Limits, fldPath.Child("limits"))
+	requests, errs := validateResourceList(resources.Requests, fldPath.Child("requests"))
+	allErrs = append(allErrs, errs...)
+
+	for _, name := range sortedKeys(requests) {
+		request, limit := requests[name], limits[name]
+		if _, ok := limits[name]; ok && request.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requests").Key(name), resources.Requests[name],
+				fmt.Sprintf("must be less than or equal to %s limit of %s", name, resources.Limits[name])))
+		}
+	}
+	return allErrs
+}
+
+// validateResourceList parses the quantities of a resource list, returning the ones that are valid.
+func validateResourceList(list observabilityv1alpha1.ResourceList, fldPath *field.Path) (map[string]resource.Quantity, field.ErrorList) {
+	quantities := map[string]resource.Quantity{}
+	var allErrs field.ErrorList
+	for _, name := range sortedKeys(list) {
+		value := list[name]
+		if msgs := validation.IsQualifiedName(name); len(msgs) > 0 {
+			for _, msg := range msgs {
+				allErrs = append(allErrs, field.Invalid(fldPath.Key(name), name, msg))
+			}
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), value, "must be a quantity, like 100m or 128Mi"))
+			continue
+		}
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), value, "must not be negative"))
+			continue
+		}
+		quantities[name] = quantity
+	}
+	return quantities, allErrs
+}
+
+// sortedKeys returns the keys of a map in order, so that errors are reported deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/hellices/openapi-aggregator-operator/api/v1alpha1"
+	observabilityv1beta1 "github.com/hellices/openapi-aggregator-operator/api/v1beta1"
+)
+
+var _ = Describe("SwaggerServer Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *observabilityv1alpha1.SwaggerServer
+		validator SwaggerServerCustomValidator
+		defaulter SwaggerServerCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &observabilityv1alpha1.SwaggerServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: observabilityv1alpha1.SwaggerServerSpec{
+				ConfigMapName: "test-specs",
+				Port:          9090,
+			},
+		}
+		validator = SwaggerServerCustomValidator{}
+		defaulter = SwaggerServerCustomDefaulter{}
+	})
+
+	Context("When defaulting", func() {
+		It("Should fill the defaults the controller applies", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.Image).To(Equal("ghcr.io/hellices/openapi-multi-swagger:latest"))
+			Expect(obj.Spec.ImagePullPolicy).To(Equal("IfNotPresent"))
+			Expect(obj.Spec.WatchIntervalSeconds).To(Equal("10"))
+			Expect(obj.Spec.LogLevel).To(Equal("info"))
+			Expect(obj.Spec.DevMode).To(Equal("false"))
+		})
+	})
+
+	Context("When validating", func() {
+		It("Should admit valid resources", func() {
+			obj.Spec.Resources = observabilityv1alpha1.ResourceRequirements{
+				Limits:   observabilityv1alpha1.ResourceList{"cpu": "500m", "memory": "256Mi"},
+				Requests: observabilityv1alpha1.ResourceList{"cpu": "100m", "memory": "256Mi"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject malformed quantities and intervals", func() {
+			obj.Spec.ConfigMapName = "Test_Specs"
+			obj.Spec.WatchIntervalSeconds = "10s"
+			obj.Spec.Resources = observabilityv1alpha1.ResourceRequirements{
+				Limits:   observabilityv1alpha1.ResourceList{"memory": "lots", "bad name": "1"},
+				Requests: observabilityv1alpha1.ResourceList{"cpu": "-1"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(Equal([]string{
+				"spec.configMapName",
+				"spec.watchIntervalSeconds",
+				"spec.resources.limits[bad name]",
+				"spec.resources.limits[memory]",
+				"spec.resources.requests[cpu]",
+			}))
+		})
+
+		It("Should reject requests above limits", func() {
+			obj.Spec.Resources = observabilityv1alpha1.ResourceRequirements{
+				Limits:   observabilityv1alpha1.ResourceList{"memory": "128Mi"},
+				Requests: observabilityv1alpha1.ResourceList{"memory": "256Mi"},
+			}
+
+			_, err := validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)
+			Expect(causeFields(err)).To(Equal([]string{"spec.resources.requests[memory]"}))
+		})
+	})
+
+	Context("When converting", func() {
+		It("Should convert to v1beta1 with typed fields", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			obj.Spec.DevMode = "true"
+			obj.Spec.Resources = observabilityv1alpha1.ResourceRequirements{
+				Limits: observabilityv1alpha1.ResourceList{"memory": "256Mi"},
+			}
+
+			hub := &observabilityv1beta1.SwaggerServer{}
+			Expect(obj.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.WatchInterval.Duration).To(Equal(10 * time.Second))
+			Expect(hub.Spec.DevMode).To(BeTrue())
+			Expect(hub.Spec.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+			Expect(hub.Spec.Resources.Limits).To(HaveLen(1))
+			Expect(hub.Spec.Resources.Limits.Memory().String()).To(Equal("256Mi"))
+			Expect(hub.Annotations).To(BeEmpty())
+
+			converted := &observabilityv1alpha1.SwaggerServer{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted).To(Equal(obj))
+		})
+
+		It("Should keep values that v1beta1 cannot represent", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			obj.Spec.WatchIntervalSeconds = "soon"
+			obj.Spec.Resources = observabilityv1alpha1.ResourceRequirements{
+				Limits:   observabilityv1alpha1.ResourceList{"memory": "lots", "cpu": "0.5"},
+				Requests: observabilityv1alpha1.ResourceList{"cpu": "100m"},
+			}
+
+			hub := &observabilityv1beta1.SwaggerServer{}
+			Expect(obj.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.WatchInterval).To(BeNil())
+			Expect(hub.Spec.Resources.Limits).To(HaveLen(1))
+			Expect(hub.Spec.Resources.Limits.Cpu().String()).To(Equal("500m"))
+
+			converted := &observabilityv1alpha1.SwaggerServer{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted).To(Equal(obj))
+
+			By("changing the values through v1beta1")
+			hub.Spec.WatchInterval = &metav1.Duration{Duration: 1500 * time.Millisecond}
+			hub.Spec.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1Gi")
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted.Spec.WatchIntervalSeconds).To(Equal("2"))
+			Expect(converted.Spec.Resources.Limits).To(Equal(observabilityv1alpha1.ResourceList{"memory": "1Gi", "cpu": "500m"}))
+		})
+	})
+})